@@ -2,25 +2,48 @@ package app
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"log/slog"
 	"sync"
+	"time"
 	"video-ai-stt/config"
+	"video-ai-stt/internal/api"
+	"video-ai-stt/internal/chunker"
 	"video-ai-stt/internal/extractor"
+	"video-ai-stt/internal/failure"
 	"video-ai-stt/internal/groq"
 	"video-ai-stt/internal/job"
+	"video-ai-stt/internal/openai"
 	"video-ai-stt/internal/process"
+	"video-ai-stt/internal/stt"
+	"video-ai-stt/internal/subtitle"
 	"video-ai-stt/internal/watcher"
+	"video-ai-stt/internal/whisper"
 	"video-ai-stt/logger"
 )
 
 type App struct {
-	cfg        *config.AISttConfig
-	watcher    *watcher.Watcher
-	extractor  *extractor.Extractor
-	groqClient *groq.Groq
-	videoCh    chan *job.Job
-	audioCh    chan *job.Job
+	cfg          *config.AISttConfig
+	watcher      *watcher.Watcher
+	extractor    *extractor.Extractor
+	sttProcessor *stt.Processor
+	generator    *subtitle.Generator
+	api          *api.Server
+	submitter    *api.Submitter
+	processed    *process.ProcessedManager
+	videoCh      chan *job.Job
+	audioCh      chan *job.Job
+	transcriptCh chan *job.Job
+
+	// ingest 작업을 유입시키는 watcher, api, recovery / pipeline 추출, 전사, 자막 생성 단계
+	ingestWg     sync.WaitGroup
+	pipelineWg   sync.WaitGroup
+	stopIngest   context.CancelFunc
+	stopPipeline context.CancelFunc
+
+	// failed watcher 가 멈춰 작업이 더 이상 유입되지 않는 경우, 앱을 종료하도록 알림
+	failed chan error
 }
 
 func NewApplication() *App {
@@ -34,26 +57,92 @@ func NewApplication() *App {
 		log.Fatalf("fail to init slog err : %v", err)
 	}
 
-	manager := process.NewProcessedManager()
+	store, err := process.NewStore(cfg.Store)
+	if err != nil {
+		log.Fatalf("fail to open job store err : %v", err)
+	}
+
+	manager, err := process.NewProcessedManager(store)
+	if err != nil {
+		log.Fatalf("fail to load job store err : %v", err)
+	}
+
+	recorder := failure.NewRecorder(cfg.Failure, manager)
+
+	transcriber, err := newTranscriber(cfg)
+	if err != nil {
+		log.Fatalf("fail to create transcriber err : %v", err)
+	}
+
+	generator, err := subtitle.NewGenerator(cfg.Subtitle, manager, recorder)
+	if err != nil {
+		log.Fatalf("fail to create subtitle generator err : %v", err)
+	}
+
+	for _, profile := range cfg.Profiles {
+		for _, format := range profile.OutputFormats {
+			if _, err := subtitle.Lookup(format); err != nil {
+				log.Fatalf("fail to load profile %s err : %v", profile.Name, err)
+			}
+		}
+	}
+
+	if err := api.CheckConfig(cfg.API); err != nil {
+		log.Fatalf("fail to load api config err : %v", err)
+	}
+
+	// 단계 사이 queue, 가득 차면 watcher 는 등록을 멈추고 api 요청은 자리가 날 때까지 대기
+	videoCh := make(chan *job.Job, cfg.QueueSize)
+	submitter := api.NewSubmitter(cfg.WatcherFiles, manager, videoCh)
 
 	return &App{
-		cfg:        cfg,
-		watcher:    watcher.NewWatcher(cfg.WatcherFiles, manager),
-		extractor:  extractor.NewExtractor(cfg.Extractor, manager),
-		videoCh:    make(chan *job.Job),
-		audioCh:    make(chan *job.Job),
-		groqClient: groq.NewGroq(cfg.Groq, manager),
+		cfg:          cfg,
+		watcher:      watcher.NewWatcher(cfg.WatcherFiles, manager),
+		extractor:    extractor.NewExtractor(cfg.Extractor, manager, recorder),
+		videoCh:      videoCh,
+		audioCh:      make(chan *job.Job, cfg.QueueSize),
+		transcriptCh: make(chan *job.Job, cfg.QueueSize),
+		sttProcessor: stt.NewProcessor(cfg.STT, transcriber, manager, recorder),
+		generator:    generator,
+		api:          api.NewServer(cfg.API, manager, submitter),
+		submitter:    submitter,
+		processed:    manager,
+		failed:       make(chan error, 1),
 	}
 }
 
+// Start 파이프라인 단계를 먼저 띄운 뒤 작업 유입을 시작
+func (a *App) Start() {
+	pipelineCtx, stopPipeline := context.WithCancel(context.Background())
+	ingestCtx, stopIngest := context.WithCancel(context.Background())
+	a.stopPipeline = stopPipeline
+	a.stopIngest = stopIngest
+
+	a.pipelineWg.Add(3)
+	go a.ExtractAudio(pipelineCtx, &a.pipelineWg)
+	go a.TranscribeAudio(pipelineCtx, &a.pipelineWg)
+	go a.GenerateSubtitle(pipelineCtx, &a.pipelineWg)
+
+	a.ingestWg.Add(3)
+	go a.WatcherVideoFiles(ingestCtx, &a.ingestWg)
+	go a.RecoverJobs(ingestCtx, &a.ingestWg)
+	go a.ServeAPI(ingestCtx, &a.ingestWg)
+}
+
 func (a *App) WatcherVideoFiles(ctx context.Context, wg *sync.WaitGroup) {
 	defer wg.Done()
 
 	if err := a.watcher.Process(ctx, a.videoCh); err != nil {
 		slog.Error("fail to watcher process", "watcher_dir", a.cfg.WatcherDir, "error", err.Error())
+		a.failed <- err
 	}
 }
 
+// Failed watcher 가 오류로 멈추면 값을 전달, 작업 유입이 끊긴 채로 실행되지 않도록 종료에 사용
+func (a *App) Failed() <-chan error {
+	return a.failed
+}
+
 func (a *App) ExtractAudio(ctx context.Context, wg *sync.WaitGroup) {
 	defer wg.Done()
 
@@ -62,13 +151,116 @@ func (a *App) ExtractAudio(ctx context.Context, wg *sync.WaitGroup) {
 	}
 }
 
+func (a *App) TranscribeAudio(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	if err := a.sttProcessor.Process(ctx, a.audioCh, a.transcriptCh); err != nil {
+		slog.Error("fail to stt process", "provider", a.cfg.Provider, "error", err.Error())
+	}
+}
+
 func (a *App) GenerateSubtitle(ctx context.Context, wg *sync.WaitGroup) {
 	defer wg.Done()
 
-	if err := a.groqClient.Process(ctx, a.audioCh); err != nil {
-		slog.Error("fail to groq client process", "error", err.Error())
+	if err := a.generator.Process(ctx, a.transcriptCh); err != nil {
+		slog.Error("fail to subtitle generator process", "error", err.Error())
 	}
 }
 
+func (a *App) ServeAPI(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	if !a.cfg.API.Enabled {
+		slog.Debug("api server disabled")
+		return
+	}
+
+	if err := a.api.Run(ctx); err != nil {
+		slog.Error("fail to api server process", "addr", a.cfg.API.Addr, "error", err.Error())
+	}
+}
+
+// newTranscriber 설정된 STT_PROVIDER 에 해당하는 Transcriber 생성
+// 업로드 제한을 넘는 오디오는 chunker 가 분할하여 공급자에 전달
+func newTranscriber(cfg *config.AISttConfig) (stt.Transcriber, error) {
+	retry := stt.NewRetryPolicy(cfg.STT)
+
+	var transcriber stt.Transcriber
+	switch cfg.Provider {
+	case stt.PROVIDER_GROQ:
+		transcriber = groq.NewGroq(cfg.Groq, retry)
+	case stt.PROVIDER_OPENAI:
+		transcriber = openai.NewOpenAI(cfg.OpenAI, retry)
+	case stt.PROVIDER_WHISPER:
+		transcriber = whisper.NewWhisper(cfg.Whisper, retry)
+	default:
+		return nil, fmt.Errorf("unsupported stt provider: %s", cfg.Provider)
+	}
+
+	return chunker.NewChunker(cfg.Chunker, transcriber), nil
+}
+
+// Stop watcher, api, recovery 를 멈춘 뒤 videoCh 를 닫아 추출 → 전사 → 자막 생성 순서로 남은 작업을 처리
+// STT_SHUTDOWN_TIMEOUT 안에 끝나지 않으면 진행 중인 작업을 중단하고 재시작 시 이어서 처리하도록 기록
 func (a *App) Stop() {
+	deadline := time.Now().Add(a.cfg.Pipeline.ShutdownTimeout)
+
+	slog.Info("stop ingest, watcher and api")
+	a.stopIngest()
+	// queue 자리를 기다리던 api 요청이 바로 반환되어야 api 서버 종료가 STT_API_SHUTDOWN_TIMEOUT 까지 밀리지 않음
+	a.submitter.Close()
+	a.ingestWg.Wait()
+
+	// 각 단계는 입력 channel 이 닫히고 진행 중인 작업이 끝나면 다음 단계의 channel 을 닫음
+	slog.Info("drain pipeline", "video_queue_depth", len(a.videoCh), "audio_queue_depth", len(a.audioCh), "transcript_queue_depth", len(a.transcriptCh))
+	close(a.videoCh)
+
+	if !waitTimeout(&a.pipelineWg, time.Until(deadline)) {
+		slog.Warn("shutdown deadline exceeded, interrupt in-flight jobs", "shutdown_timeout", a.cfg.Pipeline.ShutdownTimeout.String())
+		a.stopPipeline()
+
+		if !waitTimeout(&a.pipelineWg, extractor.STOP_GRACE_PERIOD+time.Second) {
+			slog.Warn("pipeline not stopped, stop without waiting")
+		}
+	}
+	a.stopPipeline()
+
+	a.reportUnfinished()
+}
+
+// reportUnfinished 종료 시점에 끝나지 않은 작업 (재시작 시 RecoverJobs 가 이어서 처리)
+func (a *App) reportUnfinished() {
+	unfinished := 0
+	for _, record := range a.processed.Records() {
+		if process.IsFinished(record.Step) {
+			continue
+		}
+		unfinished++
+		slog.Warn("unfinished job, resume after restart", "rid", record.RID, "video_path", record.VideoPath, "step", record.Step, "updated_at", record.UpdatedAt)
+	}
+	slog.Info("pipeline stopped", "unfinished_count", unfinished)
+}
+
+func waitTimeout(wg *sync.WaitGroup, timeout time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+func (a *App) Close() {
+	if err := a.processed.Close(); err != nil {
+		slog.Error("fail to close job store", "error", err.Error())
+	}
 }
@@ -0,0 +1,71 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"sync"
+	"video-ai-stt/internal/job"
+	"video-ai-stt/internal/process"
+)
+
+// RecoverJobs 비정상 종료로 중단된 작업을 마지막 완료 단계 기준으로 파이프라인에 재투입
+func (a *App) RecoverJobs(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	for _, record := range a.processed.Records() {
+		if record.Step >= process.ALL_PROCESS_COMPLETE {
+			continue
+		}
+
+		jobs := job.RestoreJob(record.RID, record.VideoPath, record.AudioPath, record.TranscriptPath, record.Filename, record.Step)
+		jobs.SetMetadata(record.Metadata)
+		jobs.SetMedia(record.Media)
+		jobs.SetSilences(record.Silences)
+		jobs.SetProfile(record.Profile)
+		logger := slog.With("rid", jobs.GetRID(), "video_path", jobs.GetVideoPath(), "audio_path", jobs.GetAudioPath(), "recorded_step", record.Step)
+
+		switch {
+		case record.Step >= process.REQUEST_GROQ_API_END && fileExists(record.TranscriptPath):
+			logger.Info("recover job, regenerate subtitle from saved transcript", "step", process.REQUEST_GROQ_API_END)
+			a.processed.MarkProcessed(jobs, process.REQUEST_GROQ_API_END)
+			if !sendJob(ctx, a.transcriptCh, jobs) {
+				return
+			}
+
+		case record.Step >= process.EXTRACT_AUDIO_COMPLETE && fileExists(record.AudioPath):
+			logger.Info("recover job, resume from extracted audio", "step", process.EXTRACT_AUDIO_COMPLETE)
+			a.processed.MarkProcessed(jobs, process.EXTRACT_AUDIO_COMPLETE)
+			if !sendJob(ctx, a.audioCh, jobs) {
+				return
+			}
+
+		case fileExists(record.VideoPath):
+			logger.Info("recover job, resume from video", "step", process.WATCHER_FILE_REGISTER)
+			a.processed.MarkProcessed(jobs, process.WATCHER_FILE_REGISTER)
+			if !sendJob(ctx, a.videoCh, jobs) {
+				return
+			}
+
+		default:
+			logger.Warn("failed recover job, source file not found")
+		}
+	}
+}
+
+func sendJob(ctx context.Context, ch chan<- *job.Job, jobs *job.Job) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case ch <- jobs:
+		return true
+	}
+}
+
+func fileExists(path string) bool {
+	if path == "" {
+		return false
+	}
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -1,11 +1,9 @@
 package main
 
 import (
-	"context"
 	"log/slog"
 	"os"
 	"os/signal"
-	"sync"
 	"syscall"
 	"video-ai-stt/cmd/ai-stt/app"
 )
@@ -16,28 +14,23 @@ var APP_VERSION = "no flag of APP_VERSION"
 
 func main() {
 
-	wg := sync.WaitGroup{}
-	ctx, cancel := context.WithCancel(context.Background())
-
 	a := app.NewApplication()
-
-	wg.Add(1)
-	go a.WatcherVideoFiles(ctx, &wg)
-
-	wg.Add(1)
-	go a.ExtractAudio(ctx, &wg)
-
-	wg.Add(1)
-	go a.GenerateSubtitle(ctx, &wg)
+	a.Start()
 
 	slog.Debug("ai stt app start", "git_hash", GIT_HASH, "build_time", BUILD_TIME, "app_version", APP_VERSION)
 
-	<-exitSignal()
+	exitCode := 0
+	select {
+	case <-exitSignal():
+	case err := <-a.Failed():
+		slog.Error("ai stt app stop, watcher failed", "error", err.Error())
+		exitCode = 1
+	}
 	a.Stop()
-	cancel()
-	wg.Wait()
+	a.Close()
 
 	slog.Debug("ai stt app gracefully stopped")
+	os.Exit(exitCode)
 }
 
 func exitSignal() <-chan os.Signal {
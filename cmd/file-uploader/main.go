@@ -1,7 +1,64 @@
 package main
 
-import "fmt"
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"video-ai-stt/config"
+	"video-ai-stt/internal/uploader"
+	"video-ai-stt/logger"
+)
 
+// file-uploader 영상을 chunk 단위로 uploads/.working 에 올린 뒤 완료되면 uploads/ 로 옮김
+// 중단된 경우 같은 명령을 다시 실행하면 이어서 업로드
 func main() {
-	fmt.Println("file-uploader")
+
+	checksum := flag.String("sha256", "", "expected sha256 of the source file (optional, single file only)")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [-sha256 <hex>] <video file>...\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() == 0 || (*checksum != "" && flag.NArg() > 1) {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cfg, err := config.LoadUploaderEnvConfig()
+	if err != nil {
+		log.Fatalf("fail to read config err: %v", err)
+	}
+
+	if err := logger.SlogInit(cfg.Logger); err != nil {
+		log.Fatalf("fail to init slog err : %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	u := uploader.NewUploader(cfg.Uploader, cfg.WatcherFiles)
+
+	failed := 0
+	for _, source := range flag.Args() {
+		destPath, err := u.Upload(ctx, source, *checksum)
+		if err != nil {
+			slog.Error("fail to upload file", "source_path", source, "error", err.Error())
+			failed++
+			if ctx.Err() != nil {
+				break
+			}
+			continue
+		}
+		fmt.Println(destPath)
+	}
+
+	if failed > 0 {
+		os.Exit(1)
+	}
 }
@@ -1,31 +1,191 @@
 package config
 
-import "github.com/kelseyhightower/envconfig"
+import (
+	"fmt"
+	"github.com/kelseyhightower/envconfig"
+	"os"
+	"time"
+)
 
 type AISttConfig struct {
 	WatcherFiles
 	Extractor
 	Logger
+	STT
+	Subtitle
+	Chunker
 	Groq
+	OpenAI
+	Whisper
+	Store
+	Failure
+	API
+	Pipeline
+}
+
+type STT struct {
+	Provider string `envconfig:"STT_PROVIDER" default:"groq"`
+	// Concurrency 동시에 전사하는 작업 수, 작업 하나는 chunker 의 STT_CHUNK_CONCURRENCY 만큼 동시에 요청할 수 있음
+	Concurrency int `envconfig:"STT_TRANSCRIBE_CONCURRENCY" default:"4"`
+	// TranscriptDir 자막 생성 단계의 입력이 되는 transcript 저장 위치
+	TranscriptDir string `envconfig:"STT_TRANSCRIPT_DIR" default:"./output"`
+
+	RetryMaxAttempts int           `envconfig:"STT_RETRY_MAX_ATTEMPTS" default:"5"`
+	RetryBaseBackoff time.Duration `envconfig:"STT_RETRY_BASE_BACKOFF" default:"1s"`
+	RetryMaxBackoff  time.Duration `envconfig:"STT_RETRY_MAX_BACKOFF" default:"60s"`
+	RetryJitter      float64       `envconfig:"STT_RETRY_JITTER" default:"0.2"`
+
+	// Timeout 작업 하나의 전사 제한 시간 (chunk, 재시도 포함), RequestTimeout 요청 한 번의 업로드와 응답 제한 시간, 0 이면 제한 없음
+	Timeout        time.Duration `envconfig:"STT_TRANSCRIBE_TIMEOUT" default:"1h"`
+	RequestTimeout time.Duration `envconfig:"STT_REQUEST_TIMEOUT" default:"10m"`
+
+	// 프로필과 작업 metadata 에 값이 없을 때 사용하는 언어 힌트, prompt, 용어 목록
+	Language       string   `envconfig:"STT_LANGUAGE" default:""`
+	Prompt         string   `envconfig:"STT_PROMPT" default:""`
+	VocabularyFile string   `envconfig:"STT_VOCABULARY_FILE" default:""`
+	Vocabulary     []string `ignored:"true"`
+	// PromptMaxTokens prompt 와 용어를 합친 길이 제한 (whisper 는 마지막 224 토큰만 사용), 0 이면 제한 없음
+	PromptMaxTokens int `envconfig:"STT_PROMPT_MAX_TOKENS" default:"224"`
+}
+
+type Subtitle struct {
+	Concurrency    int           `envconfig:"STT_SUBTITLE_CONCURRENCY" default:"4"`
+	Timeout        time.Duration `envconfig:"STT_SUBTITLE_TIMEOUT" default:"1m"`
+	OutputDir      string        `envconfig:"STT_RESULT_DIR" default:"./output"`
+	OutputFormats  []string      `envconfig:"STT_OUTPUT_FORMATS" default:"json,srt,vtt,words.json"`
+	VTTCueSettings string        `envconfig:"STT_VTT_CUE_SETTINGS" default:""`
+
+	// 단어 타임스탬프 기준 cue 재분할 (가독성 기준), 줄 길이는 표시 폭(한글 2칸) 기준
+	Resegment       bool          `envconfig:"STT_CUE_RESEGMENT" default:"true"`
+	MaxCharsPerLine int           `envconfig:"STT_CUE_MAX_CHARS_PER_LINE" default:"42"`
+	MaxLines        int           `envconfig:"STT_CUE_MAX_LINES" default:"2"`
+	MaxCPS          float64       `envconfig:"STT_CUE_MAX_CPS" default:"17"`
+	MinCueDuration  time.Duration `envconfig:"STT_CUE_MIN_DURATION" default:"1s"`
+	MaxCueDuration  time.Duration `envconfig:"STT_CUE_MAX_DURATION" default:"7s"`
+	MinCueGap       time.Duration `envconfig:"STT_CUE_MIN_GAP" default:"80ms"`
+}
+
+type Chunker struct {
+	MaxUploadBytes int64         `envconfig:"STT_CHUNK_MAX_UPLOAD_BYTES" default:"26214400"`
+	ChunkDuration  time.Duration `envconfig:"STT_CHUNK_DURATION" default:"10m"`
+	Overlap        time.Duration `envconfig:"STT_CHUNK_OVERLAP" default:"5s"`
+	Concurrency    int           `envconfig:"STT_CHUNK_CONCURRENCY" default:"3"`
+	WorkDir        string        `envconfig:"STT_CHUNK_WORK_DIR" default:"./extract_audio/chunks"`
+}
+
+// validate 분할 길이가 0 이하이면 chunk 를 끝없이 만들게 되므로 시작하지 않음
+func (c Chunker) validate() error {
+	if c.MaxUploadBytes <= 0 {
+		return fmt.Errorf("STT_CHUNK_MAX_UPLOAD_BYTES must be positive, got %d", c.MaxUploadBytes)
+	}
+	if c.ChunkDuration <= 0 {
+		return fmt.Errorf("STT_CHUNK_DURATION must be positive, got %s", c.ChunkDuration)
+	}
+	if c.Overlap < 0 {
+		return fmt.Errorf("STT_CHUNK_OVERLAP must not be negative, got %s", c.Overlap)
+	}
+	return nil
 }
 
 type Groq struct {
 	APIToken    string `envconfig:"GROQ_API_KEY" default:""`
 	STTEndpoint string `envconfig:"GROQ_STT_ENDPOINT" default:"https://api.groq.com/openai/v1/audio/transcriptions"`
 	STTUseModel string `envconfig:"GROQ_STT_USE_MODEL" default:"whisper-large-v3-turbo"`
-	OutputDir   string `envconfig:"GROQ_OUTPUT_DIR" default:"./output"`
+}
+
+type OpenAI struct {
+	APIToken       string `envconfig:"OPENAI_API_KEY" default:""`
+	STTEndpoint    string `envconfig:"OPENAI_STT_ENDPOINT" default:"https://api.openai.com/v1/audio/transcriptions"`
+	STTUseModel    string `envconfig:"OPENAI_STT_USE_MODEL" default:"whisper-1"`
+	ResponseFormat string `envconfig:"OPENAI_STT_RESPONSE_FORMAT" default:"verbose_json"`
+}
+
+type Whisper struct {
+	APIToken    string `envconfig:"WHISPER_API_KEY" default:""`
+	STTEndpoint string `envconfig:"WHISPER_STT_ENDPOINT" default:"http://localhost:8080/inference"`
+	STTUseModel string `envconfig:"WHISPER_STT_USE_MODEL" default:""`
 }
 
 type WatcherFiles struct {
 	WatcherDir    string `envconfig:"STT_WATCHER_DIR" default:"./uploads"`
 	WatchInterval int    `envconfig:"STT_WATCH_INTERVAL" default:"5"`
 	IgnoreDir     string `envconfig:"STT_WATCH_IGNORE_DIR" default:".working"`
+
+	// 미디어 여부는 extractor 에서 ffprobe 로 확인, 확장자 목록은 ffprobe 전에 후보를 거르는 용도
+	// MediaExtensions 설정 시 해당 확장자만 후보 ("*" 또는 빈 값이면 모든 파일), ExcludeExtensions 는 항상 제외
+	MediaExtensions   []string `envconfig:"STT_WATCH_EXTENSIONS" default:""`
+	ExcludeExtensions []string `envconfig:"STT_WATCH_EXCLUDE_EXTENSIONS" default:".srt,.vtt,.txt,.json,.md,.nfo,.jpg,.jpeg,.png,.gif,.pdf,.zip"`
+
+	// WatchMode poll: WatchInterval 마다 전체 탐색, event: inotify 이벤트 기반 (linux 외에는 poll 로 동작)
+	WatchMode         string        `envconfig:"STT_WATCH_MODE" default:"poll"`
+	ReconcileInterval time.Duration `envconfig:"STT_WATCH_RECONCILE_INTERVAL" default:"1m"`
+
+	// 크기와 수정 시각이 StablePeriod 동안 바뀌지 않아야 등록, 0 이면 검사하지 않음
+	StablePeriod     time.Duration `envconfig:"STT_WATCH_STABLE_PERIOD" default:"10s"`
+	LockSuffixes     []string      `envconfig:"STT_WATCH_LOCK_SUFFIXES" default:".part,.lock,.tmp,.partial,.crdownload"`
+	CheckOpenWriters bool          `envconfig:"STT_WATCH_CHECK_OPEN_WRITERS" default:"false"`
+
+	// ProfilesFile 추가 watch 디렉토리와 디렉토리별 프로필 (json), WatcherDir 은 default 프로필로 항상 감시
+	ProfilesFile string    `envconfig:"STT_WATCH_PROFILES_FILE" default:""`
+	Profiles     []Profile `ignored:"true"`
 }
 
 type Extractor struct {
+	// Concurrency 동시에 실행하는 ffmpeg 추출 작업 수
+	Concurrency      int    `envconfig:"STT_EXTRACT_CONCURRENCY" default:"2"`
 	OutputDir        string `envconfig:"STT_OUTPUT_DIR" default:"./extract_audio"`
 	OutputSampleRate string `envconfig:"STT_OUTPUT_BITRATE" default:"16000"`
 	OutputFormat     string `envconfig:"STT_OUTPUT_FORMAT" default:".flac"`
+
+	// AudioPassthrough 오디오만 있는 입력 중 추출 없이 그대로 전사할 확장자
+	AudioPassthrough []string `envconfig:"STT_AUDIO_PASSTHROUGH" default:".flac,.mp3,.m4a,.ogg,.wav"`
+
+	SilenceDetect      bool    `envconfig:"STT_SILENCE_DETECT" default:"true"`
+	SilenceNoise       string  `envconfig:"STT_SILENCE_NOISE" default:"-30dB"`
+	SilenceMinDuration float64 `envconfig:"STT_SILENCE_MIN_DURATION" default:"0.5"`
+
+	// 추출과 무음 구간 검출은 각각 Timeout + 미디어 길이 × TimeoutRatio 까지 실행, 0 이면 제한 없음
+	ProbeTimeout time.Duration `envconfig:"STT_PROBE_TIMEOUT" default:"1m"`
+	Timeout      time.Duration `envconfig:"STT_EXTRACT_TIMEOUT" default:"5m"`
+	TimeoutRatio float64       `envconfig:"STT_EXTRACT_TIMEOUT_RATIO" default:"0.5"`
+}
+
+type Store struct {
+	Type string `envconfig:"STT_STORE_TYPE" default:"bolt"`
+	Path string `envconfig:"STT_STORE_PATH" default:"./data/jobs.db"`
+}
+
+type Failure struct {
+	Dir string `envconfig:"STT_FAILED_DIR" default:"./failed"`
+}
+
+type API struct {
+	Enabled bool `envconfig:"STT_API_ENABLED" default:"true"`
+	// Addr 기본값은 loopback, 외부에 노출하는 주소는 Token 이 있어야 시작
+	Addr string `envconfig:"STT_API_ADDR" default:"127.0.0.1:8090"`
+	// Token 설정 시 Authorization: Bearer <token> 헤더 필요
+	Token             string        `envconfig:"STT_API_TOKEN" default:""`
+	MaxUploadBytes    int64         `envconfig:"STT_API_MAX_UPLOAD_BYTES" default:"10737418240"`
+	ReadHeaderTimeout time.Duration `envconfig:"STT_API_READ_HEADER_TIMEOUT" default:"10s"`
+	ShutdownTimeout   time.Duration `envconfig:"STT_API_SHUTDOWN_TIMEOUT" default:"10s"`
+}
+
+// Pipeline 단계 사이 queue 설정, queue 가 가득 차면 watcher 는 등록을 멈추고 다음 탐색에서 다시 시도
+type Pipeline struct {
+	QueueSize int `envconfig:"STT_QUEUE_SIZE" default:"16"`
+	// ShutdownTimeout 종료 신호 이후 진행 중인 작업의 정리를 기다리는 최대 시간, 중단된 작업은 재시작 시 이어서 처리
+	ShutdownTimeout time.Duration `envconfig:"STT_SHUTDOWN_TIMEOUT" default:"30s"`
+}
+
+// UploaderConfig file-uploader 설정, 업로드 대상은 ai-stt 의 watcher 디렉토리
+type UploaderConfig struct {
+	WatcherFiles
+	Logger
+	Uploader
+}
+
+type Uploader struct {
+	ChunkSize int64 `envconfig:"STT_UPLOAD_CHUNK_SIZE" default:"8388608"`
 }
 
 type Logger struct {
@@ -34,10 +194,70 @@ type Logger struct {
 	PrintStdOut bool   `envconfig:"STT_LOG_STDOUT" default:"true"`
 }
 
+// legacyEnvs 이름이 바뀐 환경 변수 (새 이름 → 이전 이름), 새 이름이 설정되지 않은 경우 이전 이름의 값을 사용
+// GROQ_OUTPUT_DIR 은 자막과 transcript 를 함께 저장하던 위치
+var legacyEnvs = []struct {
+	name   string
+	legacy string
+}{
+	{name: "STT_RESULT_DIR", legacy: "GROQ_OUTPUT_DIR"},
+	{name: "STT_TRANSCRIPT_DIR", legacy: "GROQ_OUTPUT_DIR"},
+	{name: "STT_RETRY_MAX_ATTEMPTS", legacy: "GROQ_RETRY_MAX_ATTEMPTS"},
+	{name: "STT_RETRY_BASE_BACKOFF", legacy: "GROQ_RETRY_BASE_BACKOFF"},
+	{name: "STT_RETRY_MAX_BACKOFF", legacy: "GROQ_RETRY_MAX_BACKOFF"},
+	{name: "STT_RETRY_JITTER", legacy: "GROQ_RETRY_JITTER"},
+}
+
+func applyLegacyEnvs() error {
+	for _, env := range legacyEnvs {
+		if _, ok := os.LookupEnv(env.name); ok {
+			continue
+		}
+		if value, ok := os.LookupEnv(env.legacy); ok {
+			if err := os.Setenv(env.name, value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 func LoadAISttEnvConfig() (*AISttConfig, error) {
+	if err := applyLegacyEnvs(); err != nil {
+		return nil, err
+	}
+
 	var config AISttConfig
 	if err := envconfig.Process("stt", &config); err != nil {
 		return nil, err
 	}
+
+	if err := config.Chunker.validate(); err != nil {
+		return nil, err
+	}
+
+	if config.ProfilesFile != "" {
+		profiles, err := LoadProfiles(config.ProfilesFile)
+		if err != nil {
+			return nil, err
+		}
+		config.Profiles = profiles
+	}
+
+	if config.VocabularyFile != "" {
+		vocabulary, err := LoadVocabulary(config.VocabularyFile)
+		if err != nil {
+			return nil, err
+		}
+		config.Vocabulary = vocabulary
+	}
+	return &config, nil
+}
+
+func LoadUploaderEnvConfig() (*UploaderConfig, error) {
+	var config UploaderConfig
+	if err := envconfig.Process("stt", &config); err != nil {
+		return nil, err
+	}
 	return &config, nil
 }
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestLegacyEnvs(t *testing.T) {
+	t.Cleanup(func() {
+		for _, env := range legacyEnvs {
+			os.Unsetenv(env.name)
+		}
+	})
+	t.Setenv("GROQ_OUTPUT_DIR", "/data/legacy")
+	t.Setenv("GROQ_RETRY_MAX_ATTEMPTS", "9")
+	t.Setenv("STT_RETRY_JITTER", "0.5")
+	t.Setenv("GROQ_RETRY_JITTER", "0.9")
+
+	cfg, err := LoadAISttEnvConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Subtitle.OutputDir != "/data/legacy" || cfg.TranscriptDir != "/data/legacy" {
+		t.Errorf("output dirs = %s, %s, want GROQ_OUTPUT_DIR", cfg.Subtitle.OutputDir, cfg.TranscriptDir)
+	}
+	if cfg.RetryMaxAttempts != 9 {
+		t.Errorf("RetryMaxAttempts = %d, want 9", cfg.RetryMaxAttempts)
+	}
+	if cfg.RetryJitter != 0.5 {
+		t.Errorf("RetryJitter = %v, new name must take precedence", cfg.RetryJitter)
+	}
+}
+
+func TestChunkerValidate(t *testing.T) {
+	valid := Chunker{MaxUploadBytes: 1, ChunkDuration: time.Minute, Overlap: time.Second}
+
+	tests := []struct {
+		name    string
+		modify  func(c *Chunker)
+		wantErr bool
+	}{
+		{name: "valid", modify: func(c *Chunker) {}},
+		{name: "zero upload bytes", modify: func(c *Chunker) { c.MaxUploadBytes = 0 }, wantErr: true},
+		{name: "zero duration", modify: func(c *Chunker) { c.ChunkDuration = 0 }, wantErr: true},
+		{name: "negative duration", modify: func(c *Chunker) { c.ChunkDuration = -time.Second }, wantErr: true},
+		{name: "negative overlap", modify: func(c *Chunker) { c.Overlap = -time.Second }, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := valid
+			tt.modify(&cfg)
+			if err := cfg.validate(); (err != nil) != tt.wantErr {
+				t.Errorf("validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadRejectsInvalidChunker(t *testing.T) {
+	t.Setenv("STT_CHUNK_DURATION", "0s")
+
+	if _, err := LoadAISttEnvConfig(); err == nil {
+		t.Error("LoadAISttEnvConfig() accepted STT_CHUNK_DURATION=0s")
+	}
+}
@@ -0,0 +1,57 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+const DEFAULT_PROFILE = "default"
+
+// Profile watch 디렉토리별 파이프라인 설정, 비어 있는 값은 전역 설정을 사용
+type Profile struct {
+	Name     string `json:"name"`
+	WatchDir string `json:"watch_dir"`
+	Language string `json:"language,omitempty"`
+	Model    string `json:"model,omitempty"`
+	Prompt   string `json:"prompt,omitempty"`
+	// Vocabulary 프로필 용어 목록, VocabularyFile 의 용어는 뒤에 추가
+	Vocabulary     []string `json:"vocabulary,omitempty"`
+	VocabularyFile string   `json:"vocabulary_file,omitempty"`
+	OutputDir      string   `json:"output_dir,omitempty"`
+	OutputFormats  []string `json:"output_formats,omitempty"`
+	SampleRate     string   `json:"sample_rate,omitempty"`
+}
+
+// LoadProfiles STT_WATCH_PROFILES_FILE 의 프로필 목록 (json 배열)
+func LoadProfiles(path string) ([]Profile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading profiles file: %w", err)
+	}
+
+	var profiles []Profile
+	if err := json.Unmarshal(data, &profiles); err != nil {
+		return nil, fmt.Errorf("failed unmarshalling profiles file: %w", err)
+	}
+
+	names := map[string]bool{DEFAULT_PROFILE: true}
+	for i, profile := range profiles {
+		if profile.Name == "" || profile.WatchDir == "" {
+			return nil, fmt.Errorf("profile requires name and watch_dir, profile: %+v", profile)
+		}
+		if names[profile.Name] {
+			return nil, fmt.Errorf("duplicate profile name: %s", profile.Name)
+		}
+		names[profile.Name] = true
+
+		if profile.VocabularyFile != "" {
+			vocabulary, err := LoadVocabulary(profile.VocabularyFile)
+			if err != nil {
+				return nil, fmt.Errorf("profile %s: %w", profile.Name, err)
+			}
+			profiles[i].Vocabulary = append(profile.Vocabulary, vocabulary...)
+		}
+	}
+	return profiles, nil
+}
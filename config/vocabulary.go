@@ -0,0 +1,32 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// LoadVocabulary 한 줄에 하나씩 적힌 용어 목록 (고유명사, 전문 용어), 빈 줄과 # 주석은 무시
+// 앞에 적힌 용어일수록 prompt 길이 제한에서 우선 포함
+func LoadVocabulary(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed opening vocabulary file: %w", err)
+	}
+	defer file.Close()
+
+	var vocabulary []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		term := strings.TrimSpace(scanner.Text())
+		if term == "" || strings.HasPrefix(term, "#") {
+			continue
+		}
+		vocabulary = append(vocabulary, term)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed reading vocabulary file: %w", err)
+	}
+	return vocabulary, nil
+}
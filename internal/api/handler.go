@@ -0,0 +1,324 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"video-ai-stt/internal/job"
+	"video-ai-stt/internal/process"
+)
+
+const (
+	DEFAULT_LIST_LIMIT = 100
+	MAX_LIST_LIMIT     = 1000
+	MAX_FIELD_BYTES    = 4096
+)
+
+type submitRequest struct {
+	Path string `json:"path"`
+	job.Metadata
+}
+
+// submitJob multipart/form-data 의 file 파트 업로드 또는 json {"path": "..."} 로 watcher 디렉토리 안의 파일 등록
+// title, language, requester, profile, prompt, vocabulary(쉼표 구분) 는 작업 metadata 로 기록 (multipart 는 file 파트보다 앞에 위치해야 함)
+func (s *Server) submitJob(w http.ResponseWriter, r *http.Request) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+
+	switch mediaType {
+	case "multipart/form-data":
+		s.submitUpload(w, r)
+	case "application/json":
+		req := submitRequest{}
+		if err := json.NewDecoder(io.LimitReader(r.Body, 1<<20)).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+			return
+		}
+		if req.Path == "" {
+			writeError(w, http.StatusBadRequest, "path is required")
+			return
+		}
+
+		jobs, err := s.submitter.SubmitPath(r.Context(), req.Path, req.Metadata)
+		if err != nil {
+			writeSubmitError(w, err)
+			return
+		}
+		s.writeRecord(w, http.StatusAccepted, jobs.GetVideoPath())
+	default:
+		writeError(w, http.StatusUnsupportedMediaType, "content type must be multipart/form-data or application/json")
+	}
+}
+
+func (s *Server) submitUpload(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, s.cfg.MaxUploadBytes)
+
+	reader, err := r.MultipartReader()
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid multipart body: %v", err))
+		return
+	}
+
+	metadata := job.Metadata{}
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			writeError(w, http.StatusBadRequest, "file part is required")
+			return
+		}
+		if err != nil {
+			writeSubmitError(w, err)
+			return
+		}
+
+		if part.FormName() != "file" || part.FileName() == "" {
+			value, err := io.ReadAll(io.LimitReader(part, MAX_FIELD_BYTES))
+			part.Close()
+			if err != nil {
+				writeSubmitError(w, err)
+				return
+			}
+			setMetadata(&metadata, part.FormName(), string(value))
+			continue
+		}
+
+		jobs, err := s.submitter.SubmitUpload(part.FileName(), metadata, part)
+		part.Close()
+		if err != nil {
+			writeSubmitError(w, err)
+			return
+		}
+		s.writeRecord(w, http.StatusAccepted, jobs.GetVideoPath())
+		return
+	}
+}
+
+func (s *Server) getJob(w http.ResponseWriter, r *http.Request) {
+	record, ok := s.processed.LoadByRID(r.PathValue("rid"))
+	if !ok {
+		writeError(w, http.StatusNotFound, process.ErrJobNotFound.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, s.jobResponse(record))
+}
+
+// listJobs status, step, q(파일명), since, until, limit, offset 필터로 작업 목록 조회 (최근 등록 순)
+func (s *Server) listJobs(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseListFilter(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var records []process.Record
+	for _, record := range s.processed.Records() {
+		if filter.match(record) {
+			records = append(records, record)
+		}
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].CreatedAt.After(records[j].CreatedAt)
+	})
+
+	resp := listResponse{Total: len(records), Jobs: []jobResponse{}}
+	if filter.offset < len(records) {
+		records = records[filter.offset:]
+		if len(records) > filter.limit {
+			records = records[:filter.limit]
+		}
+		for _, record := range records {
+			resp.Jobs = append(resp.Jobs, s.jobResponse(record))
+		}
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) cancelJob(w http.ResponseWriter, r *http.Request) {
+	record, err := s.processed.Cancel(r.PathValue("rid"))
+	switch {
+	case errors.Is(err, process.ErrJobNotFound):
+		writeError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, process.ErrJobFinished):
+		writeError(w, http.StatusConflict, fmt.Sprintf("%s: %s", err.Error(), jobStatus(record.Step)))
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err.Error())
+	default:
+		writeJSON(w, http.StatusOK, s.jobResponse(record))
+	}
+}
+
+func (s *Server) downloadArtifact(w http.ResponseWriter, r *http.Request) {
+	record, ok := s.processed.LoadByRID(r.PathValue("rid"))
+	if !ok {
+		writeError(w, http.StatusNotFound, process.ErrJobNotFound.Error())
+		return
+	}
+
+	format := r.PathValue("format")
+	path, ok := artifacts(record)[format]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("artifact not found: %s", format))
+		return
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("artifact not available: %s", format))
+		return
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filepath.Base(path)}))
+	http.ServeContent(w, r, filepath.Base(path), info.ModTime(), file)
+}
+
+func (s *Server) health(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func (s *Server) writeRecord(w http.ResponseWriter, status int, videoPath string) {
+	record, ok := s.processed.Load(videoPath)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "job record not found after submit")
+		return
+	}
+	writeJSON(w, status, s.jobResponse(record))
+}
+
+// setMetadata multipart 필드와 tus Upload-Metadata 의 key 를 작업 metadata 로 반영
+func setMetadata(metadata *job.Metadata, key, value string) {
+	value = strings.TrimSpace(value)
+	switch key {
+	case "title":
+		metadata.Title = value
+	case "language":
+		metadata.Language = value
+	case "requester":
+		metadata.Requester = value
+	case "profile":
+		metadata.Profile = value
+	case "prompt":
+		metadata.Prompt = value
+	case "vocabulary":
+		metadata.Vocabulary = splitVocabulary(value)
+	}
+}
+
+// splitVocabulary 쉼표나 줄바꿈으로 구분된 용어 목록
+func splitVocabulary(value string) []string {
+	var vocabulary []string
+	for _, term := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == '\n' }) {
+		if term = strings.TrimSpace(term); term != "" {
+			vocabulary = append(vocabulary, term)
+		}
+	}
+	return vocabulary
+}
+
+type listFilter struct {
+	statuses map[string]bool
+	step     int
+	query    string
+	since    time.Time
+	until    time.Time
+	limit    int
+	offset   int
+}
+
+func parseListFilter(r *http.Request) (listFilter, error) {
+	query := r.URL.Query()
+	filter := listFilter{
+		query: strings.ToLower(query.Get("q")),
+		limit: DEFAULT_LIST_LIMIT,
+	}
+
+	if statuses := query.Get("status"); statuses != "" {
+		filter.statuses = make(map[string]bool)
+		for _, status := range strings.Split(statuses, ",") {
+			filter.statuses[strings.TrimSpace(status)] = true
+		}
+	}
+
+	var err error
+	if v := query.Get("step"); v != "" {
+		if filter.step, err = strconv.Atoi(v); err != nil {
+			return filter, fmt.Errorf("invalid step: %s", v)
+		}
+	}
+	if v := query.Get("since"); v != "" {
+		if filter.since, err = time.Parse(time.RFC3339, v); err != nil {
+			return filter, fmt.Errorf("invalid since, expected RFC3339: %s", v)
+		}
+	}
+	if v := query.Get("until"); v != "" {
+		if filter.until, err = time.Parse(time.RFC3339, v); err != nil {
+			return filter, fmt.Errorf("invalid until, expected RFC3339: %s", v)
+		}
+	}
+	if v := query.Get("limit"); v != "" {
+		if filter.limit, err = strconv.Atoi(v); err != nil || filter.limit <= 0 {
+			return filter, fmt.Errorf("invalid limit: %s", v)
+		}
+		filter.limit = min(filter.limit, MAX_LIST_LIMIT)
+	}
+	if v := query.Get("offset"); v != "" {
+		if filter.offset, err = strconv.Atoi(v); err != nil || filter.offset < 0 {
+			return filter, fmt.Errorf("invalid offset: %s", v)
+		}
+	}
+	return filter, nil
+}
+
+func (f listFilter) match(record process.Record) bool {
+	if f.statuses != nil && !f.statuses[jobStatus(record.Step)] {
+		return false
+	}
+	if f.step != 0 && record.Step != f.step {
+		return false
+	}
+	if f.query != "" && !strings.Contains(strings.ToLower(record.Filename), f.query) {
+		return false
+	}
+	if !f.since.IsZero() && record.CreatedAt.Before(f.since) {
+		return false
+	}
+	if !f.until.IsZero() && record.CreatedAt.After(f.until) {
+		return false
+	}
+	return true
+}
+
+func writeSubmitError(w http.ResponseWriter, err error) {
+	var maxBytesErr *http.MaxBytesError
+
+	switch {
+	case errors.Is(err, ErrInvalidPath), errors.Is(err, ErrUnknownProfile), errors.Is(err, ErrProfileMismatch):
+		writeError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, ErrUnsupportedMedia):
+		writeError(w, http.StatusUnsupportedMediaType, err.Error())
+	case errors.Is(err, ErrAlreadySubmitted):
+		writeError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, ErrQueueUnavailable):
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+	case errors.Is(err, os.ErrNotExist):
+		writeError(w, http.StatusNotFound, err.Error())
+	case errors.As(err, &maxBytesErr):
+		writeError(w, http.StatusRequestEntityTooLarge, err.Error())
+	default:
+		writeError(w, http.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,140 @@
+package api
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+	"video-ai-stt/internal/job"
+	"video-ai-stt/internal/media"
+	"video-ai-stt/internal/process"
+)
+
+var stepNames = map[int]string{
+	process.WATCHER_FILE_REGISTER:      "watcher_file_register",
+	process.EXTRACT_AUDIO_START:        "extract_audio_start",
+	process.EXTRACT_AUDIO_COMPLETE:     "extract_audio_complete",
+	process.REQUEST_GROQ_API_START:     "request_stt_start",
+	process.REQUEST_GROQ_API_END:       "request_stt_end",
+	process.GENERATE_SUBTITLE_START:    "generate_subtitle_start",
+	process.GENERATE_SUBTITLE_COMPLETE: "generate_subtitle_complete",
+	process.ALL_PROCESS_COMPLETE:       "all_process_complete",
+	process.JOB_CANCELLED:              "job_cancelled",
+	process.EXTRACT_AUDIO_FAILED:       "extract_audio_failed",
+	process.REQUEST_GROQ_API_FAILED:    "request_stt_failed",
+	process.GENERATE_SUBTITLE_FAILED:   "generate_subtitle_failed",
+	process.PROBE_MEDIA_FAILED:         "probe_media_failed",
+}
+
+type jobResponse struct {
+	RID        string                  `json:"rid"`
+	Filename   string                  `json:"filename"`
+	VideoPath  string                  `json:"video_path"`
+	Status     string                  `json:"status"`
+	Step       int                     `json:"step"`
+	StepName   string                  `json:"step_name"`
+	Error      string                  `json:"error,omitempty"`
+	Reason     string                  `json:"reason,omitempty"`
+	FailedPath string                  `json:"failed_path,omitempty"`
+	Profile    string                  `json:"profile,omitempty"`
+	Artifacts  map[string]string       `json:"artifacts,omitempty"`
+	Metadata   job.Metadata            `json:"metadata"`
+	Media      *media.Info             `json:"media,omitempty"`
+	Upload     *process.UploadProgress `json:"upload_progress,omitempty"`
+	CreatedAt  time.Time               `json:"created_at"`
+	UpdatedAt  time.Time               `json:"updated_at"`
+}
+
+type listResponse struct {
+	Total int           `json:"total"`
+	Jobs  []jobResponse `json:"jobs"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func newJobResponse(record process.Record) jobResponse {
+	resp := jobResponse{
+		RID:        record.RID,
+		Filename:   record.Filename,
+		VideoPath:  record.VideoPath,
+		Status:     jobStatus(record.Step),
+		Step:       record.Step,
+		StepName:   stepNames[record.Step],
+		Error:      record.Error,
+		Reason:     record.Reason,
+		FailedPath: record.FailedPath,
+		Profile:    record.Profile.Name,
+		Metadata:   record.Metadata,
+		Media:      record.Media,
+		CreatedAt:  record.CreatedAt,
+		UpdatedAt:  record.UpdatedAt,
+	}
+
+	// 결과물은 다운로드 경로로 노출
+	for format := range artifacts(record) {
+		if resp.Artifacts == nil {
+			resp.Artifacts = make(map[string]string)
+		}
+		resp.Artifacts[format] = "/v1/jobs/" + url.PathEscape(record.RID) + "/artifacts/" + url.PathEscape(format)
+	}
+	return resp
+}
+
+// jobResponse 전사 중인 작업은 업로드 진행 상황을 함께 노출
+func (s *Server) jobResponse(record process.Record) jobResponse {
+	resp := newJobResponse(record)
+	if progress, ok := s.processed.UploadProgress(record.RID); ok {
+		resp.Upload = &progress
+	}
+	return resp
+}
+
+// jobStatus process step 을 api 에서 사용하는 상태 이름으로 변환
+func jobStatus(step int) string {
+	switch {
+	case step == process.JOB_CANCELLED:
+		return "cancelled"
+	case process.IsFailed(step):
+		return "failed"
+	case step >= process.ALL_PROCESS_COMPLETE:
+		return "completed"
+	case step >= process.GENERATE_SUBTITLE_START:
+		return "generating"
+	case step >= process.REQUEST_GROQ_API_START:
+		return "transcribing"
+	case step >= process.EXTRACT_AUDIO_START:
+		return "extracting"
+	default:
+		return "queued"
+	}
+}
+
+// artifacts 다운로드 가능한 결과물 (형식 → 파일 경로), 자막 형식과 transcript
+func artifacts(record process.Record) map[string]string {
+	files := make(map[string]string, len(record.Artifacts)+1)
+	for format, path := range record.Artifacts {
+		files[format] = path
+	}
+	if record.TranscriptPath != "" {
+		files["transcript"] = record.TranscriptPath
+	}
+	return files
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		slog.Error("failed encoding api response", "error", err.Error())
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, errorResponse{Error: message})
+}
@@ -0,0 +1,155 @@
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+	"video-ai-stt/config"
+	"video-ai-stt/internal/process"
+)
+
+// Server 작업 등록, 상태 조회, 취소, 결과물 다운로드와 tus 업로드(/files/)를 제공하는 http 서버
+type Server struct {
+	cfg       config.API
+	processed *process.ProcessedManager
+	submitter *Submitter
+	tus       *tusStore
+	server    *http.Server
+}
+
+func NewServer(cfg config.API, manager *process.ProcessedManager, submitter *Submitter) *Server {
+	s := &Server{
+		cfg:       cfg,
+		processed: manager,
+		submitter: submitter,
+		tus:       newTusStore(filepath.Join(submitter.WorkingDir(), TUS_DIR)),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/jobs", s.submitJob)
+	mux.HandleFunc("GET /v1/jobs", s.listJobs)
+	mux.HandleFunc("GET /v1/jobs/{rid}", s.getJob)
+	mux.HandleFunc("DELETE /v1/jobs/{rid}", s.cancelJob)
+	mux.HandleFunc("GET /v1/jobs/{rid}/artifacts/{format}", s.downloadArtifact)
+	// tus client 에 따라 collection 주소를 /files 또는 /files/ 로 사용
+	mux.HandleFunc("OPTIONS /files", s.tusHandler(s.tusOptions))
+	mux.HandleFunc("OPTIONS /files/", s.tusHandler(s.tusOptions))
+	mux.HandleFunc("POST /files", s.tusHandler(s.tusCreate))
+	mux.HandleFunc("POST /files/", s.tusHandler(s.tusCreate))
+	mux.HandleFunc("HEAD /files/{id}", s.tusHandler(s.tusHead))
+	mux.HandleFunc("PATCH /files/{id}", s.tusHandler(s.tusPatch))
+	mux.HandleFunc("DELETE /files/{id}", s.tusHandler(s.tusDelete))
+	mux.HandleFunc("GET /health", s.health)
+
+	s.server = &http.Server{
+		Addr:              cfg.Addr,
+		Handler:           s.logging(s.auth(mux)),
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+	}
+	return s
+}
+
+// ErrTokenRequired loopback 이 아닌 주소에서 인증 없이 api 를 여는 설정
+var ErrTokenRequired = errors.New("STT_API_TOKEN is required when STT_API_ADDR is not a loopback address")
+
+// CheckConfig 인증 없이 외부에 노출되는 설정이면 시작하지 않도록 오류 반환
+func CheckConfig(cfg config.API) error {
+	if !cfg.Enabled || cfg.Token != "" {
+		return nil
+	}
+
+	host, _, err := net.SplitHostPort(cfg.Addr)
+	if err != nil {
+		return fmt.Errorf("invalid api addr %s: %w", cfg.Addr, err)
+	}
+	if !isLoopback(host) {
+		return fmt.Errorf("%w: %s", ErrTokenRequired, cfg.Addr)
+	}
+	return nil
+}
+
+// isLoopback 빈 host(모든 인터페이스)는 loopback 이 아님
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// Run ctx 가 종료될 때까지 요청을 처리하고 종료 시 진행 중인 요청을 기다림
+func (s *Server) Run(ctx context.Context) error {
+
+	errCh := make(chan error, 1)
+	go func() {
+		slog.Debug("api server start", "addr", s.cfg.Addr)
+		errCh <- s.server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return fmt.Errorf("failed listen api server: %w", err)
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.cfg.ShutdownTimeout)
+	defer cancel()
+
+	if err := s.server.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("failed shutdown api server: %w", err)
+	}
+	slog.Debug("close api server goroutine", "addr", s.cfg.Addr)
+	return nil
+}
+
+func (s *Server) auth(next http.Handler) http.Handler {
+	if s.cfg.Token == "" {
+		return next
+	}
+
+	expected := []byte("Bearer " + s.cfg.Token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/health" && subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), expected) != 1 {
+			writeError(w, http.StatusUnauthorized, "invalid api token")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) logging(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		if strings.HasPrefix(r.URL.Path, "/health") {
+			return
+		}
+		slog.Info("api request", "method", r.Method, "path", r.URL.Path, "status", rec.status, "elapsed", time.Since(start).String(), "remote_addr", r.RemoteAddr)
+	})
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
@@ -0,0 +1,31 @@
+package api
+
+import (
+	"errors"
+	"testing"
+	"video-ai-stt/config"
+)
+
+func TestCheckConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.API
+		wantErr error
+	}{
+		{name: "기본 loopback", cfg: config.API{Enabled: true, Addr: "127.0.0.1:8090"}},
+		{name: "localhost", cfg: config.API{Enabled: true, Addr: "localhost:8090"}},
+		{name: "ipv6 loopback", cfg: config.API{Enabled: true, Addr: "[::1]:8090"}},
+		{name: "모든 인터페이스", cfg: config.API{Enabled: true, Addr: ":8090"}, wantErr: ErrTokenRequired},
+		{name: "외부 주소", cfg: config.API{Enabled: true, Addr: "0.0.0.0:8090"}, wantErr: ErrTokenRequired},
+		{name: "외부 주소와 token", cfg: config.API{Enabled: true, Addr: "0.0.0.0:8090", Token: "secret"}},
+		{name: "비활성", cfg: config.API{Enabled: false, Addr: ":8090"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := CheckConfig(tt.cfg); !errors.Is(err, tt.wantErr) {
+				t.Errorf("CheckConfig(%+v) = %v, want %v", tt.cfg, err, tt.wantErr)
+			}
+		})
+	}
+}
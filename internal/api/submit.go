@@ -0,0 +1,294 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"video-ai-stt/config"
+	"video-ai-stt/internal/job"
+	"video-ai-stt/internal/process"
+	"video-ai-stt/internal/watcher"
+)
+
+var (
+	ErrInvalidPath      = errors.New("path is not inside watcher dir")
+	ErrUnsupportedMedia = errors.New("unsupported media file")
+	ErrAlreadySubmitted = errors.New("file already submitted")
+	ErrQueueUnavailable = errors.New("job queue unavailable")
+	ErrChecksumMismatch = errors.New("checksum mismatch")
+	ErrUnknownProfile   = errors.New("unknown profile")
+	ErrProfileMismatch  = errors.New("profile does not match watch dir of path")
+)
+
+// Submitter watcher 와 같은 방식으로 작업을 등록하고 videoCh 로 전달
+type Submitter struct {
+	cfg       config.WatcherFiles
+	processed *process.ProcessedManager
+	profiles  *watcher.Profiles
+	videoCh   chan<- *job.Job
+
+	// mu videoCh 로 전달 중인 요청이 끝난 뒤에 Close 가 반환되도록 보장
+	mu        sync.RWMutex
+	closed    bool
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func NewSubmitter(cfg config.WatcherFiles, manager *process.ProcessedManager, videoCh chan<- *job.Job) *Submitter {
+	return &Submitter{
+		cfg:       cfg,
+		processed: manager,
+		profiles:  watcher.NewProfiles(cfg),
+		videoCh:   videoCh,
+		done:      make(chan struct{}),
+	}
+}
+
+// SubmitPath watch 디렉토리 안에 이미 존재하는 파일을 작업으로 등록, 프로필은 파일이 속한 디렉토리 기준
+// metadata 의 profile 이 디렉토리의 프로필과 다르면 ErrProfileMismatch
+func (s *Submitter) SubmitPath(ctx context.Context, path string, metadata job.Metadata) (*job.Job, error) {
+	videoPath, err := s.watcherPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(videoPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed stat video file: %w", err)
+	}
+	if !info.Mode().IsRegular() {
+		return nil, fmt.Errorf("%w: not a regular file", ErrUnsupportedMedia)
+	}
+	if !s.IsMediaFile(info.Name()) {
+		return nil, ErrUnsupportedMedia
+	}
+
+	// 이미 watch 디렉토리에 있는 파일은 디렉토리의 프로필로 처리되므로 다른 프로필은 거부
+	profile := s.profiles.Match(videoPath)
+	if metadata.Profile != "" && metadata.Profile != profile.Name {
+		return nil, fmt.Errorf("%w: %s, watch dir profile %s", ErrProfileMismatch, metadata.Profile, profile.Name)
+	}
+
+	prev, hadPrev := s.processed.Load(videoPath)
+	if hadPrev && !s.processed.IsRetryable(videoPath) && prev.Step != process.JOB_CANCELLED {
+		return nil, fmt.Errorf("%w: rid %s", ErrAlreadySubmitted, prev.RID)
+	}
+
+	jobs := job.NewJob(videoPath, info.Name())
+	jobs.SetMetadata(metadata)
+	jobs.SetProfile(profile)
+	s.processed.MarkProcessed(jobs, process.WATCHER_FILE_REGISTER)
+	if err := s.enqueue(ctx, jobs); err != nil {
+		// 이번 요청의 등록만 되돌리고 이전에 취소, 실패한 기록은 복원
+		s.processed.Revert(jobs, prev, hadPrev)
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// SubmitUpload 업로드 내용을 .working 에 기록한 뒤 SubmitStaged 로 등록
+// watch 디렉토리로 옮긴 뒤에는 queue 가 가득 차 있어도 업로드 metadata 와 함께 등록 상태를 유지
+func (s *Submitter) SubmitUpload(filename string, metadata job.Metadata, src io.Reader) (*job.Job, error) {
+	filename = filepath.Base(filename)
+	if !s.IsMediaFile(filename) {
+		return nil, ErrUnsupportedMedia
+	}
+	if _, err := s.Profile(metadata.Profile); err != nil {
+		return nil, err
+	}
+
+	workingDir := s.WorkingDir()
+	if err := os.MkdirAll(workingDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed creating working dir: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(workingDir, "upload-*"+filepath.Ext(filename))
+	if err != nil {
+		return nil, fmt.Errorf("failed creating upload file: %w", err)
+	}
+	tmpPath := tmp.Name()
+
+	if _, err := io.Copy(tmp, src); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return nil, fmt.Errorf("failed writing upload file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return nil, fmt.Errorf("failed closing upload file: %w", err)
+	}
+
+	jobs, err := s.SubmitStaged(tmpPath, filename, metadata)
+	if err != nil {
+		os.Remove(tmpPath)
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// SubmitStaged .working 에 기록이 끝난 파일을 metadata.Profile 의 watch 디렉토리로 옮기고 작업으로 등록
+// 파일을 옮기기 전에 등록하므로 watcher 가 같은 파일을 중복 등록하지 않음
+// 옮긴 뒤에는 업로드를 되돌릴 수 없으므로 queue 가 가득 차 있어도 실패로 응답하지 않고 handoff 로 전달
+func (s *Submitter) SubmitStaged(stagedPath, filename string, metadata job.Metadata) (*job.Job, error) {
+	filename = filepath.Base(filename)
+	if !s.IsMediaFile(filename) {
+		return nil, ErrUnsupportedMedia
+	}
+
+	profile, err := s.Profile(metadata.Profile)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.Chmod(stagedPath, 0644); err != nil {
+		return nil, fmt.Errorf("failed chmod upload file: %w", err)
+	}
+	if err := os.MkdirAll(profile.WatchDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed creating watch dir: %w", err)
+	}
+
+	videoPath := s.availablePath(profile.WatchDir, filename)
+	jobs := job.NewJob(videoPath, filepath.Base(videoPath))
+	jobs.SetMetadata(metadata)
+	jobs.SetProfile(profile)
+	s.processed.MarkProcessed(jobs, process.WATCHER_FILE_REGISTER)
+
+	if err := os.Rename(stagedPath, videoPath); err != nil {
+		s.processed.Forget(videoPath)
+		return nil, fmt.Errorf("failed moving upload file: %w", err)
+	}
+
+	s.handoff(jobs)
+	return jobs, nil
+}
+
+// Profile 이름에 해당하는 프로필, 비어 있으면 default
+func (s *Submitter) Profile(name string) (config.Profile, error) {
+	profile, ok := s.profiles.Lookup(name)
+	if !ok {
+		return config.Profile{}, fmt.Errorf("%w: %s", ErrUnknownProfile, name)
+	}
+	return profile, nil
+}
+
+// IsMediaFile watcher 와 같은 기준으로 작업 후보 파일인지 확인
+func (s *Submitter) IsMediaFile(filename string) bool {
+	return watcher.IsMediaFile(filepath.Base(filename), s.cfg.MediaExtensions, s.cfg.ExcludeExtensions)
+}
+
+// WorkingDir 업로드 중인 파일이 기록되는 watcher 제외 디렉토리
+func (s *Submitter) WorkingDir() string {
+	return filepath.Join(s.cfg.WatcherDir, s.cfg.IgnoreDir)
+}
+
+// Close 작업 전달을 멈춤, 종료 시 videoCh 를 닫기 전에 호출
+// 전달을 기다리던 작업은 등록 상태로 남아 재시작 시 RecoverJobs 가 이어서 처리
+func (s *Submitter) Close() {
+	s.closeOnce.Do(func() {
+		close(s.done)
+	})
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+}
+
+// enqueue 작업을 videoCh 로 전달, 요청이 끝나 전달하지 못하면 ErrQueueUnavailable (등록은 호출한 쪽에서 되돌림)
+// 종료 중이면 등록 상태로 남겨 재시작 시 RecoverJobs 가 처리
+func (s *Submitter) enqueue(ctx context.Context, jobs *job.Job) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if !s.closed {
+		select {
+		case s.videoCh <- jobs:
+			slog.Info("api new file", "rid", jobs.GetRID(), "filename", jobs.GetFilename(), "video_path", jobs.GetVideoPath(), "step", process.WATCHER_FILE_REGISTER, "queue_depth", len(s.videoCh))
+			return nil
+		case <-ctx.Done():
+			return fmt.Errorf("%w: %w", ErrQueueUnavailable, ctx.Err())
+		case <-s.done:
+		}
+	}
+
+	slog.Info("api new file, queued until restart", "rid", jobs.GetRID(), "filename", jobs.GetFilename(), "video_path", jobs.GetVideoPath(), "step", process.WATCHER_FILE_REGISTER)
+	return nil
+}
+
+// handoff watch 디렉토리로 옮긴 파일의 작업을 videoCh 로 전달, 요청과 무관하게 등록 상태를 유지
+// queue 가 가득 차 있으면 자리가 날 때까지 백그라운드에서 기다림
+func (s *Submitter) handoff(jobs *job.Job) {
+	s.mu.RLock()
+	if s.closed {
+		s.mu.RUnlock()
+		slog.Info("api new file, queued until restart", "rid", jobs.GetRID(), "filename", jobs.GetFilename(), "video_path", jobs.GetVideoPath(), "step", process.WATCHER_FILE_REGISTER)
+		return
+	}
+	select {
+	case s.videoCh <- jobs:
+		s.mu.RUnlock()
+		slog.Info("api new file", "rid", jobs.GetRID(), "filename", jobs.GetFilename(), "video_path", jobs.GetVideoPath(), "step", process.WATCHER_FILE_REGISTER, "queue_depth", len(s.videoCh))
+		return
+	default:
+	}
+	s.mu.RUnlock()
+
+	slog.Info("api new file, waiting for queue", "rid", jobs.GetRID(), "filename", jobs.GetFilename(), "video_path", jobs.GetVideoPath(), "step", process.WATCHER_FILE_REGISTER, "queue_depth", len(s.videoCh), "queue_size", cap(s.videoCh))
+	go s.enqueue(context.Background(), jobs)
+}
+
+// watcherPath watcher 가 사용하는 key 와 같은 형태의 경로로 변환, watch 디렉토리 밖의 경로는 거부
+// 상대 경로는 WatcherDir 기준
+func (s *Submitter) watcherPath(path string) (string, error) {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(s.cfg.WatcherDir, path)
+	}
+
+	videoPath, ok := s.profiles.WatchPath(path)
+	if !ok {
+		return "", ErrInvalidPath
+	}
+	for _, dir := range s.profiles.Dirs() {
+		if filepath.Clean(dir) == videoPath {
+			return "", ErrInvalidPath
+		}
+	}
+	if strings.Contains(videoPath, s.cfg.IgnoreDir) {
+		return "", ErrInvalidPath
+	}
+	return videoPath, nil
+}
+
+// availablePath 같은 이름의 파일이나 작업이 있으면 _1, _2 ... 를 붙인 경로 반환
+func (s *Submitter) availablePath(dir, filename string) string {
+	ext := filepath.Ext(filename)
+	name := strings.TrimSuffix(filename, ext)
+
+	path := s.watchKey(filepath.Join(dir, filename))
+	for i := 1; s.exists(path); i++ {
+		path = s.watchKey(filepath.Join(dir, name+"_"+strconv.Itoa(i)+ext))
+	}
+	return path
+}
+
+// watchKey 다른 watch 디렉토리 하위에 있는 프로필 디렉토리의 파일도 watcher 와 같은 key 를 사용하도록 변환
+func (s *Submitter) watchKey(path string) string {
+	if key, ok := s.profiles.WatchPath(path); ok {
+		return key
+	}
+	return path
+}
+
+func (s *Submitter) exists(path string) bool {
+	if _, ok := s.processed.Load(path); ok {
+		return true
+	}
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,225 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"video-ai-stt/config"
+	"video-ai-stt/internal/job"
+	"video-ai-stt/internal/process"
+)
+
+func newTestSubmitter(t *testing.T, videoCh chan *job.Job) (*Submitter, *process.ProcessedManager) {
+	t.Helper()
+
+	store, err := process.NewStore(config.Store{Type: process.STORE_TYPE_MEMORY})
+	if err != nil {
+		t.Fatal(err)
+	}
+	manager, err := process.NewProcessedManager(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.WatcherFiles{
+		WatcherDir:      t.TempDir(),
+		IgnoreDir:       ".working",
+		MediaExtensions: []string{".mp4"},
+	}
+	return NewSubmitter(cfg, manager, videoCh), manager
+}
+
+func stageFile(t *testing.T, s *Submitter) string {
+	t.Helper()
+
+	if err := os.MkdirAll(s.WorkingDir(), 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(s.WorkingDir(), "upload.bin")
+	if err := os.WriteFile(path, []byte("data"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// 옮긴 파일은 queue 가 가득 차 있어도 metadata 와 함께 등록 상태를 유지하고 자리가 나면 전달
+func TestSubmitStagedQueueFull(t *testing.T) {
+	videoCh := make(chan *job.Job, 1)
+	videoCh <- job.NewJob("occupied.mp4", "occupied.mp4")
+	s, manager := newTestSubmitter(t, videoCh)
+	defer s.Close()
+
+	jobs, err := s.SubmitStaged(stageFile(t, s), "a.mp4", job.Metadata{Title: "lecture"})
+	if err != nil {
+		t.Fatalf("SubmitStaged() error = %v", err)
+	}
+
+	record, ok := manager.Load(jobs.GetVideoPath())
+	if !ok || record.Step != process.WATCHER_FILE_REGISTER || record.Metadata.Title != "lecture" {
+		t.Fatalf("record = %+v, registered %v", record, ok)
+	}
+	if _, err := os.Stat(jobs.GetVideoPath()); err != nil {
+		t.Fatalf("video file not moved: %v", err)
+	}
+
+	<-videoCh
+	select {
+	case got := <-videoCh:
+		if got.GetRID() != jobs.GetRID() {
+			t.Errorf("handoff rid = %s, want %s", got.GetRID(), jobs.GetRID())
+		}
+	case <-time.After(time.Second):
+		t.Fatal("job not handed off after queue drained")
+	}
+}
+
+// 종료 중이면 등록 상태로 남겨 재시작 시 이어서 처리
+func TestSubmitStagedClosed(t *testing.T) {
+	videoCh := make(chan *job.Job)
+	s, manager := newTestSubmitter(t, videoCh)
+	s.Close()
+
+	jobs, err := s.SubmitStaged(stageFile(t, s), "a.mp4", job.Metadata{})
+	if err != nil {
+		t.Fatalf("SubmitStaged() error = %v", err)
+	}
+	if _, ok := manager.Load(jobs.GetVideoPath()); !ok {
+		t.Error("record forgotten after submitter closed")
+	}
+}
+
+func TestSubmitUploadQueueFull(t *testing.T) {
+	videoCh := make(chan *job.Job, 1)
+	videoCh <- job.NewJob("occupied.mp4", "occupied.mp4")
+	s, manager := newTestSubmitter(t, videoCh)
+	defer func() {
+		<-videoCh
+		s.Close()
+	}()
+
+	jobs, err := s.SubmitUpload("lecture.mp4", job.Metadata{Title: "lecture", Requester: "api"}, strings.NewReader("data"))
+	if err != nil {
+		t.Fatalf("SubmitUpload() error = %v", err)
+	}
+
+	record, ok := manager.Load(jobs.GetVideoPath())
+	if !ok || record.Metadata.Title != "lecture" || record.Metadata.Requester != "api" {
+		t.Fatalf("record = %+v, registered %v", record, ok)
+	}
+	if entries, _ := os.ReadDir(s.WorkingDir()); len(entries) != 0 {
+		t.Errorf("working dir not empty: %d entries", len(entries))
+	}
+}
+
+// 종료 시 queue 자리를 기다리던 요청은 Close 로 바로 반환
+func TestCloseReleasesBlockedSubmit(t *testing.T) {
+	videoCh := make(chan *job.Job)
+	s, manager := newTestSubmitter(t, videoCh)
+
+	path := filepath.Join(s.cfg.WatcherDir, "a.mp4")
+	if err := os.WriteFile(path, []byte("data"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := s.SubmitPath(context.Background(), path, job.Metadata{})
+		errCh <- err
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	s.Close()
+
+	select {
+	case err := <-errCh:
+		if err != nil {
+			t.Fatalf("SubmitPath() error = %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("SubmitPath still blocked after Close")
+	}
+	if _, ok := manager.Load(path); !ok {
+		t.Error("record forgotten, want resume after restart")
+	}
+}
+
+// 요청이 끝나 queue 에 넣지 못한 경우 이번 등록만 되돌리고 이전 기록은 복원
+func TestSubmitPathTimeoutKeepsPreviousRecord(t *testing.T) {
+	tests := []struct {
+		name      string
+		cancelled bool
+	}{
+		{name: "이전 기록 없음"},
+		{name: "취소된 기록", cancelled: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			videoCh := make(chan *job.Job)
+			s, manager := newTestSubmitter(t, videoCh)
+			defer s.Close()
+
+			path := filepath.Join(s.cfg.WatcherDir, "a.mp4")
+			if err := os.WriteFile(path, []byte("data"), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			var prev process.Record
+			if tt.cancelled {
+				previous := job.NewJob(path, "a.mp4")
+				manager.MarkProcessed(previous, process.WATCHER_FILE_REGISTER)
+				var err error
+				if prev, err = manager.Cancel(previous.GetRID()); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+			defer cancel()
+			if _, err := s.SubmitPath(ctx, path, job.Metadata{}); !errors.Is(err, ErrQueueUnavailable) {
+				t.Fatalf("SubmitPath() error = %v, want ErrQueueUnavailable", err)
+			}
+
+			record, ok := manager.Load(path)
+			if !tt.cancelled {
+				if ok {
+					t.Errorf("record = %+v, want forgotten", record)
+				}
+				return
+			}
+			if !ok || record.RID != prev.RID || record.Step != process.JOB_CANCELLED {
+				t.Errorf("record = %+v, want previous cancelled record %s", record, prev.RID)
+			}
+			if _, ok := manager.LoadByRID(prev.RID); !ok {
+				t.Error("previous rid not found")
+			}
+		})
+	}
+}
+
+func TestSubmitPathProfile(t *testing.T) {
+	videoCh := make(chan *job.Job, 2)
+	s, _ := newTestSubmitter(t, videoCh)
+	defer s.Close()
+
+	path := filepath.Join(s.cfg.WatcherDir, "a.mp4")
+	if err := os.WriteFile(path, []byte("data"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.SubmitPath(context.Background(), path, job.Metadata{Profile: "lectures"}); !errors.Is(err, ErrProfileMismatch) {
+		t.Fatalf("SubmitPath(other profile) error = %v, want ErrProfileMismatch", err)
+	}
+
+	jobs, err := s.SubmitPath(context.Background(), path, job.Metadata{Profile: config.DEFAULT_PROFILE})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if jobs.GetProfile().Name != config.DEFAULT_PROFILE {
+		t.Errorf("profile = %s", jobs.GetProfile().Name)
+	}
+}
@@ -0,0 +1,445 @@
+package api
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"hash"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"video-ai-stt/internal/job"
+)
+
+// tus 1.0 resumable upload (https://tus.io/protocols/resumable-upload)
+// 지원 extension: creation, creation-with-upload, termination, checksum
+const (
+	TUS_VERSION             = "1.0.0"
+	TUS_EXTENSIONS          = "creation,creation-with-upload,termination,checksum"
+	TUS_CHECKSUM_ALGORITHMS = "sha1,sha256,md5"
+	TUS_CONTENT_TYPE        = "application/offset+octet-stream"
+	TUS_DIR                 = "tus"
+
+	// STATUS_CHECKSUM_MISMATCH checksum extension 에서 정의한 응답 코드
+	STATUS_CHECKSUM_MISMATCH = 460
+)
+
+// tusUpload .working/tus/<id>.info 에 저장되는 업로드 정보, 완료되거나 종료된 업로드의 정보는 삭제
+// 현재 offset 은 <id>.bin 파일 크기로 판단
+type tusUpload struct {
+	ID          string            `json:"id"`
+	Length      int64             `json:"length"`
+	RawMetadata string            `json:"raw_metadata,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+	RID         string            `json:"rid,omitempty"`
+	CreatedAt   time.Time         `json:"created_at"`
+}
+
+type tusStore struct {
+	dir   string
+	locks sync.Map
+}
+
+func newTusStore(dir string) *tusStore {
+	return &tusStore{dir: dir}
+}
+
+func (t *tusStore) lock(id string) func() {
+	val, _ := t.locks.LoadOrStore(id, &sync.Mutex{})
+	mu := val.(*sync.Mutex)
+	mu.Lock()
+	return mu.Unlock
+}
+
+// forget 완료, 종료되었거나 없는 업로드의 lock 제거, lock 을 잡은 상태에서 호출
+// 이미 대기 중인 요청은 업로드 정보를 찾지 못해 404 로 응답
+func (t *tusStore) forget(id string) {
+	t.locks.Delete(id)
+}
+
+func (t *tusStore) infoPath(id string) string {
+	return filepath.Join(t.dir, id+".info")
+}
+
+func (t *tusStore) dataPath(id string) string {
+	return filepath.Join(t.dir, id+".bin")
+}
+
+func (t *tusStore) load(id string) (*tusUpload, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, os.ErrNotExist
+	}
+
+	data, err := os.ReadFile(t.infoPath(id))
+	if err != nil {
+		return nil, err
+	}
+
+	upload := &tusUpload{}
+	if err := json.Unmarshal(data, upload); err != nil {
+		return nil, fmt.Errorf("failed unmarshalling upload info: %w", err)
+	}
+	return upload, nil
+}
+
+func (t *tusStore) save(upload *tusUpload) error {
+	data, err := json.Marshal(upload)
+	if err != nil {
+		return fmt.Errorf("failed marshalling upload info: %w", err)
+	}
+
+	tmpPath := t.infoPath(upload.ID) + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("failed writing upload info: %w", err)
+	}
+	return os.Rename(tmpPath, t.infoPath(upload.ID))
+}
+
+// offset 지금까지 받은 바이트 수, 이번 요청에서 완료되어 watcher 디렉토리로 옮겨진 경우 전체 길이
+func (t *tusStore) offset(upload *tusUpload) (int64, error) {
+	if upload.RID != "" {
+		return upload.Length, nil
+	}
+
+	info, err := os.Stat(t.dataPath(upload.ID))
+	if err != nil {
+		return 0, err
+	}
+	return info.Size(), nil
+}
+
+func (t *tusStore) remove(id string) error {
+	if err := os.Remove(t.dataPath(id)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return os.Remove(t.infoPath(id))
+}
+
+// tusHandler Tus-Resumable 헤더 확인 및 응답 헤더 설정
+func (s *Server) tusHandler(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Tus-Resumable", TUS_VERSION)
+
+		if r.Method != http.MethodOptions && r.Header.Get("Tus-Resumable") != TUS_VERSION {
+			w.Header().Set("Tus-Version", TUS_VERSION)
+			writeError(w, http.StatusPreconditionFailed, "unsupported tus version")
+			return
+		}
+		next(w, r)
+	}
+}
+
+func (s *Server) tusOptions(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Tus-Version", TUS_VERSION)
+	w.Header().Set("Tus-Extension", TUS_EXTENSIONS)
+	w.Header().Set("Tus-Checksum-Algorithm", TUS_CHECKSUM_ALGORITHMS)
+	w.Header().Set("Tus-Max-Size", strconv.FormatInt(s.cfg.MaxUploadBytes, 10))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// tusCreate creation extension, Upload-Metadata 의 filename 은 필수이며 title, language, requester, profile, prompt, vocabulary 는 작업 metadata 로 기록
+func (s *Server) tusCreate(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Upload-Defer-Length") != "" {
+		writeError(w, http.StatusBadRequest, "upload-defer-length is not supported")
+		return
+	}
+
+	length, err := strconv.ParseInt(r.Header.Get("Upload-Length"), 10, 64)
+	if err != nil || length <= 0 {
+		writeError(w, http.StatusBadRequest, "invalid upload-length, must be positive")
+		return
+	}
+	if length > s.cfg.MaxUploadBytes {
+		writeError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("upload-length exceeds max size %d", s.cfg.MaxUploadBytes))
+		return
+	}
+
+	rawMetadata := r.Header.Get("Upload-Metadata")
+	metadata, err := parseTusMetadata(rawMetadata)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !s.submitter.IsMediaFile(metadata["filename"]) {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("%s: filename metadata is required", ErrUnsupportedMedia.Error()))
+		return
+	}
+	if _, err := s.submitter.Profile(metadata["profile"]); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := os.MkdirAll(s.tus.dir, 0755); err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed creating upload dir: %v", err))
+		return
+	}
+
+	upload := &tusUpload{
+		ID:          uuid.NewString(),
+		Length:      length,
+		RawMetadata: rawMetadata,
+		Metadata:    metadata,
+		CreatedAt:   time.Now(),
+	}
+
+	unlock := s.tus.lock(upload.ID)
+	defer unlock()
+
+	file, err := os.OpenFile(s.tus.dataPath(upload.ID), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed creating upload file: %v", err))
+		return
+	}
+	file.Close()
+
+	if err := s.tus.save(upload); err != nil {
+		s.tus.remove(upload.ID)
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	slog.Info("tus upload created", "upload_id", upload.ID, "length", length, "filename", metadata["filename"], "requester", metadata["requester"])
+	w.Header().Set("Location", "/files/"+upload.ID)
+
+	// creation-with-upload 으로 생성 요청 body 에 첫 데이터가 포함된 경우
+	if r.Header.Get("Content-Type") == TUS_CONTENT_TYPE {
+		s.tusAppend(w, r, upload, 0, http.StatusCreated)
+		return
+	}
+
+	w.Header().Set("Upload-Offset", "0")
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (s *Server) tusHead(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	unlock := s.tus.lock(id)
+	defer unlock()
+
+	upload, err := s.tus.load(id)
+	if err != nil {
+		s.tus.forget(id)
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	offset, err := s.tus.offset(upload)
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Upload-Offset", strconv.FormatInt(offset, 10))
+	w.Header().Set("Upload-Length", strconv.FormatInt(upload.Length, 10))
+	if upload.RawMetadata != "" {
+		w.Header().Set("Upload-Metadata", upload.RawMetadata)
+	}
+	if upload.RID != "" {
+		w.Header().Set("X-Job-Rid", upload.RID)
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) tusPatch(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Content-Type") != TUS_CONTENT_TYPE {
+		writeError(w, http.StatusUnsupportedMediaType, "content-type must be "+TUS_CONTENT_TYPE)
+		return
+	}
+
+	offset, err := strconv.ParseInt(r.Header.Get("Upload-Offset"), 10, 64)
+	if err != nil || offset < 0 {
+		writeError(w, http.StatusBadRequest, "invalid upload-offset")
+		return
+	}
+
+	id := r.PathValue("id")
+	unlock := s.tus.lock(id)
+	defer unlock()
+
+	upload, err := s.tus.load(id)
+	if err != nil {
+		s.tus.forget(id)
+		writeError(w, http.StatusNotFound, "upload not found")
+		return
+	}
+
+	s.tusAppend(w, r, upload, offset, http.StatusNoContent)
+}
+
+// tusAppend 요청 body 를 upload 데이터 뒤에 이어 쓰고, 전체 길이에 도달하면 작업으로 등록
+// Upload-Checksum 이 주어졌는데 일치하지 않으면 이번 요청으로 받은 데이터는 버림
+func (s *Server) tusAppend(w http.ResponseWriter, r *http.Request, upload *tusUpload, offset int64, status int) {
+	current, err := s.tus.offset(upload)
+	if err != nil {
+		writeError(w, http.StatusNotFound, "upload data not found")
+		return
+	}
+	if offset != current {
+		w.Header().Set("Upload-Offset", strconv.FormatInt(current, 10))
+		writeError(w, http.StatusConflict, fmt.Sprintf("upload-offset mismatch, current offset %d", current))
+		return
+	}
+
+	if upload.RID == "" && current < upload.Length {
+		checksum, expected, err := parseTusChecksum(r.Header.Get("Upload-Checksum"))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if current, err = s.tusWrite(r, upload, current, checksum, expected); err != nil {
+			if errors.Is(err, ErrChecksumMismatch) {
+				writeError(w, STATUS_CHECKSUM_MISMATCH, err.Error())
+				return
+			}
+			slog.Warn("tus upload interrupted", "upload_id", upload.ID, "offset", current, "error", err.Error())
+			w.Header().Set("Upload-Offset", strconv.FormatInt(current, 10))
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+
+	if upload.RID == "" && current == upload.Length {
+		if err := s.tusComplete(upload); err != nil {
+			slog.Error("failed submit tus upload", "upload_id", upload.ID, "error", err.Error())
+			w.Header().Set("Upload-Offset", strconv.FormatInt(current, 10))
+			writeSubmitError(w, err)
+			return
+		}
+	}
+
+	if upload.RID != "" {
+		w.Header().Set("X-Job-Rid", upload.RID)
+	}
+	w.Header().Set("Upload-Offset", strconv.FormatInt(current, 10))
+	w.WriteHeader(status)
+}
+
+func (s *Server) tusWrite(r *http.Request, upload *tusUpload, offset int64, checksum hash.Hash, expected []byte) (int64, error) {
+	file, err := os.OpenFile(s.tus.dataPath(upload.ID), os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return offset, fmt.Errorf("failed opening upload file: %w", err)
+	}
+	defer file.Close()
+
+	dst := io.Writer(file)
+	if checksum != nil {
+		dst = io.MultiWriter(file, checksum)
+	}
+
+	written, copyErr := io.Copy(dst, io.LimitReader(r.Body, upload.Length-offset))
+	if checksum != nil && (copyErr != nil || !bytes.Equal(checksum.Sum(nil), expected)) {
+		if err := file.Truncate(offset); err != nil {
+			return offset + written, fmt.Errorf("failed discarding chunk: %w", err)
+		}
+		if copyErr != nil {
+			return offset, copyErr
+		}
+		return offset, fmt.Errorf("%w: upload-checksum", ErrChecksumMismatch)
+	}
+	if copyErr != nil {
+		return offset + written, copyErr
+	}
+	return offset + written, nil
+}
+
+// tusComplete 받은 파일을 watcher 디렉토리로 옮기고 upload metadata 를 가진 작업으로 등록, 이후 업로드 정보와 lock 은 삭제
+func (s *Server) tusComplete(upload *tusUpload) error {
+	metadata := job.Metadata{}
+	for key, value := range upload.Metadata {
+		setMetadata(&metadata, key, value)
+	}
+
+	jobs, err := s.submitter.SubmitStaged(s.tus.dataPath(upload.ID), upload.Metadata["filename"], metadata)
+	if err != nil {
+		return err
+	}
+
+	upload.RID = jobs.GetRID()
+	if err := s.tus.remove(upload.ID); err != nil {
+		slog.Error("failed remove tus upload info", "upload_id", upload.ID, "rid", upload.RID, "error", err.Error())
+	}
+	s.tus.forget(upload.ID)
+	slog.Info("tus upload complete", "upload_id", upload.ID, "rid", upload.RID, "video_path", jobs.GetVideoPath())
+	return nil
+}
+
+// tusDelete termination extension
+func (s *Server) tusDelete(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	unlock := s.tus.lock(id)
+	defer unlock()
+
+	if _, err := s.tus.load(id); err != nil {
+		s.tus.forget(id)
+		writeError(w, http.StatusNotFound, "upload not found")
+		return
+	}
+
+	if err := s.tus.remove(id); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	s.tus.forget(id)
+	slog.Info("tus upload terminated", "upload_id", id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// parseTusMetadata "key base64(value),key base64(value)" 형식
+func parseTusMetadata(raw string) (map[string]string, error) {
+	metadata := make(map[string]string)
+	if raw == "" {
+		return metadata, nil
+	}
+
+	for _, pair := range strings.Split(raw, ",") {
+		key, encoded, _ := strings.Cut(strings.TrimSpace(pair), " ")
+		if key == "" {
+			return nil, errors.New("invalid upload-metadata")
+		}
+
+		value, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("invalid upload-metadata value of %s", key)
+		}
+		metadata[key] = string(value)
+	}
+	return metadata, nil
+}
+
+// parseTusChecksum "algorithm base64(checksum)" 형식, 헤더가 없으면 nil
+func parseTusChecksum(raw string) (hash.Hash, []byte, error) {
+	if raw == "" {
+		return nil, nil, nil
+	}
+
+	algorithm, encoded, _ := strings.Cut(raw, " ")
+	expected, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, nil, errors.New("invalid upload-checksum")
+	}
+
+	switch algorithm {
+	case "sha1":
+		return sha1.New(), expected, nil
+	case "sha256":
+		return sha256.New(), expected, nil
+	case "md5":
+		return md5.New(), expected, nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported checksum algorithm: %s", algorithm)
+	}
+}
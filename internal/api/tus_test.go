@@ -0,0 +1,210 @@
+package api
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"video-ai-stt/config"
+	"video-ai-stt/internal/job"
+)
+
+func TestParseTusMetadata(t *testing.T) {
+	encode := func(value string) string {
+		return base64.StdEncoding.EncodeToString([]byte(value))
+	}
+
+	tests := []struct {
+		name    string
+		raw     string
+		want    map[string]string
+		wantErr bool
+	}{
+		{name: "빈 헤더", raw: "", want: map[string]string{}},
+		{name: "여러 key", raw: "filename " + encode("강의.mp4") + ",language " + encode("ko"), want: map[string]string{"filename": "강의.mp4", "language": "ko"}},
+		{name: "공백 포함", raw: " filename " + encode("a.mp4") + " , title " + encode("제목"), want: map[string]string{"filename": "a.mp4", "title": "제목"}},
+		{name: "값 없는 key", raw: "is_confidential", want: map[string]string{"is_confidential": ""}},
+		{name: "빈 항목", raw: "filename " + encode("a.mp4") + ",", wantErr: true},
+		{name: "base64 아님", raw: "filename not-base64!", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseTusMetadata(tt.raw)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseTusMetadata(%q) error = %v, wantErr %v", tt.raw, err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseTusMetadata(%q) = %v, want %v", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTusChecksum(t *testing.T) {
+	digest := base64.StdEncoding.EncodeToString([]byte("0123456789abcdef0123"))
+
+	tests := []struct {
+		name     string
+		raw      string
+		wantHash bool
+		wantErr  bool
+	}{
+		{name: "헤더 없음", raw: ""},
+		{name: "sha1", raw: "sha1 " + digest, wantHash: true},
+		{name: "sha256", raw: "sha256 " + digest, wantHash: true},
+		{name: "md5", raw: "md5 " + digest, wantHash: true},
+		{name: "지원하지 않는 알고리즘", raw: "crc32 " + digest, wantErr: true},
+		{name: "base64 아님", raw: "sha1 ???", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checksum, expected, err := parseTusChecksum(tt.raw)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseTusChecksum(%q) error = %v, wantErr %v", tt.raw, err, tt.wantErr)
+			}
+			if (checksum != nil) != tt.wantHash {
+				t.Errorf("parseTusChecksum(%q) hash = %v, want hash %v", tt.raw, checksum, tt.wantHash)
+			}
+			if tt.wantHash && string(expected) != "0123456789abcdef0123" {
+				t.Errorf("parseTusChecksum(%q) expected = %q", tt.raw, expected)
+			}
+		})
+	}
+}
+
+func newTestTusServer(t *testing.T) (*Server, chan *job.Job) {
+	t.Helper()
+
+	videoCh := make(chan *job.Job, 4)
+	submitter, manager := newTestSubmitter(t, videoCh)
+	t.Cleanup(submitter.Close)
+	return NewServer(config.API{MaxUploadBytes: 1 << 20}, manager, submitter), videoCh
+}
+
+func tusRequest(t *testing.T, s *Server, method, target string, header map[string]string, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set("Tus-Resumable", TUS_VERSION)
+	for key, value := range header {
+		req.Header.Set(key, value)
+	}
+	rec := httptest.NewRecorder()
+	s.server.Handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func countLocks(s *Server) int {
+	count := 0
+	s.tus.locks.Range(func(key, value any) bool {
+		count++
+		return true
+	})
+	return count
+}
+
+func TestTusOptions(t *testing.T) {
+	s, _ := newTestTusServer(t)
+
+	for _, target := range []string{"/files", "/files/"} {
+		rec := tusRequest(t, s, http.MethodOptions, target, nil, "")
+		if rec.Code != http.StatusNoContent || rec.Header().Get("Tus-Version") != TUS_VERSION {
+			t.Errorf("OPTIONS %s = %d, Tus-Version %q", target, rec.Code, rec.Header().Get("Tus-Version"))
+		}
+	}
+}
+
+func TestTusCreateRejectsZeroLength(t *testing.T) {
+	s, _ := newTestTusServer(t)
+
+	rec := tusRequest(t, s, http.MethodPost, "/files", map[string]string{
+		"Upload-Length":   "0",
+		"Upload-Metadata": "filename " + base64.StdEncoding.EncodeToString([]byte("a.mp4")),
+	}, "")
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("create with zero length = %d, want 400", rec.Code)
+	}
+}
+
+// 완료된 업로드는 작업으로 등록한 뒤 업로드 정보와 lock 을 남기지 않음
+func TestTusCompleteRemovesUploadInfo(t *testing.T) {
+	s, videoCh := newTestTusServer(t)
+
+	rec := tusRequest(t, s, http.MethodPost, "/files/", map[string]string{
+		"Upload-Length":   "8",
+		"Upload-Metadata": "filename " + base64.StdEncoding.EncodeToString([]byte("a.mp4")),
+		"Content-Type":    TUS_CONTENT_TYPE,
+	}, "data")
+	if rec.Code != http.StatusCreated || rec.Header().Get("Upload-Offset") != "4" {
+		t.Fatalf("create = %d, offset %s", rec.Code, rec.Header().Get("Upload-Offset"))
+	}
+	location := rec.Header().Get("Location")
+
+	rec = tusRequest(t, s, http.MethodPatch, location, map[string]string{
+		"Upload-Offset": "4",
+		"Content-Type":  TUS_CONTENT_TYPE,
+	}, "more")
+	if rec.Code != http.StatusNoContent || rec.Header().Get("X-Job-Rid") == "" {
+		t.Fatalf("patch = %d, rid %q", rec.Code, rec.Header().Get("X-Job-Rid"))
+	}
+
+	select {
+	case jobs := <-videoCh:
+		if jobs.GetRID() != rec.Header().Get("X-Job-Rid") {
+			t.Errorf("queued rid = %s", jobs.GetRID())
+		}
+	default:
+		t.Error("completed upload not queued")
+	}
+
+	entries, err := os.ReadDir(s.tus.dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("tus dir not cleaned: %v", entries)
+	}
+	if n := countLocks(s); n != 0 {
+		t.Errorf("lock entries = %d, want 0", n)
+	}
+
+	if rec := tusRequest(t, s, http.MethodHead, location, nil, ""); rec.Code != http.StatusNotFound {
+		t.Errorf("HEAD after complete = %d, want 404", rec.Code)
+	}
+	if n := countLocks(s); n != 0 {
+		t.Errorf("lock entries after HEAD = %d, want 0", n)
+	}
+}
+
+func TestTusDeleteRemovesUpload(t *testing.T) {
+	s, _ := newTestTusServer(t)
+
+	rec := tusRequest(t, s, http.MethodPost, "/files", map[string]string{
+		"Upload-Length":   "8",
+		"Upload-Metadata": "filename " + base64.StdEncoding.EncodeToString([]byte("a.mp4")),
+	}, "")
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create = %d", rec.Code)
+	}
+	location := rec.Header().Get("Location")
+
+	if rec := tusRequest(t, s, http.MethodDelete, location, nil, ""); rec.Code != http.StatusNoContent {
+		t.Fatalf("delete = %d", rec.Code)
+	}
+
+	entries, _ := os.ReadDir(s.tus.dir)
+	if len(entries) != 0 || countLocks(s) != 0 {
+		t.Errorf("after delete entries = %v, locks = %d", entries, countLocks(s))
+	}
+	if rec := tusRequest(t, s, http.MethodPatch, location, map[string]string{"Upload-Offset": "0", "Content-Type": TUS_CONTENT_TYPE}, "data"); rec.Code != http.StatusNotFound {
+		t.Errorf("patch after delete = %d, want 404", rec.Code)
+	}
+	if countLocks(s) != 0 {
+		t.Errorf("lock entries after 404 = %d", countLocks(s))
+	}
+}
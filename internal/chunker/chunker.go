@@ -0,0 +1,228 @@
+package chunker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+	"video-ai-stt/config"
+	"video-ai-stt/internal/extractor"
+	"video-ai-stt/internal/stt"
+)
+
+const (
+	// chunk 는 원본 형식과 관계없이 16kHz mono FLAC 으로 다시 인코딩
+	CHUNK_SAMPLE_RATE = "16000"
+	CHUNK_CHANNELS    = 1
+	// CHUNK_MAX_BYTES_PER_SECOND 16kHz 16bit mono 무압축 크기, FLAC 출력은 이보다 커지지 않음
+	CHUNK_MAX_BYTES_PER_SECOND = 16000 * 2
+)
+
+// Chunker 업로드 제한을 넘는 오디오를 겹치는 구간으로 분할해 병렬 변환 후 하나의 Transcript 로 합치는 Transcriber
+type Chunker struct {
+	cfg   config.Chunker
+	inner stt.Transcriber
+}
+
+func NewChunker(cfg config.Chunker, inner stt.Transcriber) *Chunker {
+	return &Chunker{
+		cfg:   cfg,
+		inner: inner,
+	}
+}
+
+func (c *Chunker) Name() string {
+	return c.inner.Name()
+}
+
+func (c *Chunker) Transcribe(ctx context.Context, audioPath string, opts stt.Options) (*stt.Transcript, error) {
+
+	info, err := os.Stat(audioPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed stat audio file: %w", err)
+	}
+
+	if info.Size() <= c.cfg.MaxUploadBytes {
+		return c.inner.Transcribe(ctx, audioPath, opts)
+	}
+
+	duration, err := extractor.ProbeDuration(ctx, audioPath)
+	if err != nil {
+		return nil, err
+	}
+
+	chunks := plan(duration, c.chunkLength(info.Size(), duration), c.cfg.Overlap.Seconds(), opts.Silences)
+	logger := slog.With("rid", opts.RID, "audio_path", audioPath, "provider", c.Name())
+	logger.Info("audio exceeds upload limit, split into chunks", "size", info.Size(), "max_upload_bytes", c.cfg.MaxUploadBytes, "duration", duration, "chunk_count", len(chunks))
+
+	workDir := filepath.Join(c.cfg.WorkDir, chunkDirName(audioPath, opts.RID))
+	if err := os.MkdirAll(workDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed creating chunk work dir: %w", err)
+	}
+	defer os.RemoveAll(workDir)
+
+	for i := range chunks {
+		chunks[i].Path = filepath.Join(workDir, fmt.Sprintf("chunk_%03d.flac", chunks[i].Index))
+		if err := split(ctx, audioPath, chunks[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	transcripts, err := c.transcribeChunks(ctx, logger, chunks, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	transcript := merge(chunks, transcripts)
+	transcript.Duration = duration
+	logger.Info("merged chunk transcripts", "chunk_count", len(chunks), "segment_count", len(transcript.Segments), "word_count", len(transcript.Words))
+	return transcript, nil
+}
+
+// chunkLength 업로드 제한의 90% 안에 들어오는 길이(초)
+// 압축된 원본(mp3, m4a 등)도 FLAC 으로 다시 인코딩하므로 원본과 FLAC 출력 중 큰 비트레이트를 기준으로 계산
+func (c *Chunker) chunkLength(size int64, duration float64) float64 {
+	length := c.cfg.ChunkDuration.Seconds()
+	if duration <= 0 {
+		return length
+	}
+
+	bytesPerSecond := math.Max(float64(size)/duration, CHUNK_MAX_BYTES_PER_SECOND)
+	if limit := float64(c.cfg.MaxUploadBytes) * 0.9 / bytesPerSecond; limit > 0 && limit < length {
+		length = limit
+	}
+	return length
+}
+
+// transcribeChunks chunk 들을 동시에 변환, 하나라도 실패하면 나머지 요청은 취소
+func (c *Chunker) transcribeChunks(ctx context.Context, logger *slog.Logger, chunks []Chunk, opts stt.Options) ([]*stt.Transcript, error) {
+
+	concurrency := c.cfg.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	transcripts := make([]*stt.Transcript, len(chunks))
+	sem := make(chan struct{}, concurrency)
+	wg := sync.WaitGroup{}
+	progress := newChunkProgress(chunks, opts.Progress)
+
+	var once sync.Once
+	var firstErr error
+	fail := func(chunk Chunk, err error) {
+		once.Do(func() {
+			firstErr = fmt.Errorf("failed transcribe chunk %d: %w", chunk.Index, err)
+			cancel()
+		})
+	}
+
+LOOP:
+	for i, chunk := range chunks {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			break LOOP
+		}
+
+		wg.Add(1)
+		go func(i int, chunk Chunk) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			chunkOpts := opts
+			chunkOpts.Progress = progress.chunk(i)
+
+			logger.Debug("transcribe chunk", "chunk_index", chunk.Index, "chunk_start", chunk.Start, "chunk_end", chunk.End)
+			transcript, err := c.inner.Transcribe(ctx, chunk.Path, chunkOpts)
+			if err != nil {
+				fail(chunk, err)
+				return
+			}
+			transcripts[i] = transcript
+		}(i, chunk)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	for _, transcript := range transcripts {
+		if transcript == nil {
+			return nil, ctx.Err()
+		}
+	}
+	return transcripts, nil
+}
+
+// chunkProgress 동시에 업로드되는 chunk 들의 진행 상황을 하나로 합산
+// 아직 시작하지 않은 chunk 는 파일 크기를 전체 크기로 계산
+type chunkProgress struct {
+	mu     sync.Mutex
+	sent   []int64
+	total  []int64
+	report stt.ProgressFunc
+}
+
+func newChunkProgress(chunks []Chunk, report stt.ProgressFunc) *chunkProgress {
+	p := &chunkProgress{
+		sent:   make([]int64, len(chunks)),
+		total:  make([]int64, len(chunks)),
+		report: report,
+	}
+	for i, chunk := range chunks {
+		if info, err := os.Stat(chunk.Path); err == nil {
+			p.total[i] = info.Size()
+		}
+	}
+	return p
+}
+
+func (p *chunkProgress) chunk(i int) stt.ProgressFunc {
+	if p.report == nil {
+		return nil
+	}
+
+	return func(sent, total int64) {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+
+		p.sent[i], p.total[i] = sent, total
+		var sentSum, totalSum int64
+		for j := range p.sent {
+			sentSum += p.sent[j]
+			totalSum += p.total[j]
+		}
+		p.report(sentSum, totalSum)
+	}
+}
+
+func split(ctx context.Context, audioPath string, chunk Chunk) error {
+	cmd := extractor.NewFFmpegBuilder().
+		Seek(chunk.Start).
+		Input(audioPath).
+		Duration(chunk.End - chunk.Start).
+		MapAudio().
+		AudioSampleRate(CHUNK_SAMPLE_RATE).
+		AudioChannels(CHUNK_CHANNELS).
+		UseFlacCodec().
+		Output(chunk.Path).
+		Build(ctx)
+
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed split audio chunk %d, err: %w, output: %s", chunk.Index, err, string(output))
+	}
+	return nil
+}
+
+func chunkDirName(audioPath, rid string) string {
+	if rid != "" {
+		return rid
+	}
+	return filepath.Base(audioPath)
+}
@@ -0,0 +1,100 @@
+package chunker
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+	"video-ai-stt/config"
+	"video-ai-stt/internal/stt"
+)
+
+// fakeTranscriber failPath 는 바로 실패하고, 나머지는 ctx 가 취소될 때까지 대기
+type fakeTranscriber struct {
+	failPath string
+
+	mu        sync.Mutex
+	cancelled []string
+}
+
+func (f *fakeTranscriber) Name() string {
+	return "fake"
+}
+
+func (f *fakeTranscriber) Transcribe(ctx context.Context, audioPath string, opts stt.Options) (*stt.Transcript, error) {
+	if audioPath == f.failPath {
+		return nil, errors.New("upload failed")
+	}
+
+	select {
+	case <-ctx.Done():
+		f.mu.Lock()
+		f.cancelled = append(f.cancelled, audioPath)
+		f.mu.Unlock()
+		return nil, ctx.Err()
+	case <-time.After(5 * time.Second):
+		return &stt.Transcript{}, nil
+	}
+}
+
+func TestTranscribeChunksCancelsSiblings(t *testing.T) {
+	inner := &fakeTranscriber{failPath: "chunk_1"}
+	c := NewChunker(config.Chunker{Concurrency: 2}, inner)
+	chunks := []Chunk{
+		{Index: 0, Path: "chunk_0"},
+		{Index: 1, Path: "chunk_1"},
+		{Index: 2, Path: "chunk_2"},
+		{Index: 3, Path: "chunk_3"},
+	}
+
+	started := time.Now()
+	_, err := c.transcribeChunks(context.Background(), slog.Default(), chunks, stt.Options{})
+	if err == nil || err.Error() != "failed transcribe chunk 1: upload failed" {
+		t.Fatalf("err = %v", err)
+	}
+	if elapsed := time.Since(started); elapsed > 2*time.Second {
+		t.Errorf("siblings were not cancelled, took %v", elapsed)
+	}
+	if len(inner.cancelled) == 0 {
+		t.Error("no sibling request observed cancellation")
+	}
+}
+
+func TestTranscribeChunksParentCancel(t *testing.T) {
+	inner := &fakeTranscriber{}
+	c := NewChunker(config.Chunker{Concurrency: 2}, inner)
+	chunks := []Chunk{{Index: 0, Path: "chunk_0"}, {Index: 1, Path: "chunk_1"}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if _, err := c.transcribeChunks(ctx, slog.Default(), chunks, stt.Options{}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want deadline exceeded", err)
+	}
+}
+
+func TestChunkLength(t *testing.T) {
+	c := NewChunker(config.Chunker{MaxUploadBytes: 10_000_000, ChunkDuration: 10 * time.Minute}, &fakeTranscriber{})
+
+	tests := []struct {
+		name     string
+		size     int64
+		duration float64
+		want     float64
+	}{
+		// 128kbps mp3 도 FLAC 출력 비트레이트로 계산
+		{"compressed source", 16_000 * 3600, 3600, 10_000_000 * 0.9 / CHUNK_MAX_BYTES_PER_SECOND},
+		{"source above flac rate", 100_000 * 3600, 3600, 90},
+		{"unknown duration", 1_000, 0, 600},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.chunkLength(tt.size, tt.duration); got != tt.want {
+				t.Errorf("chunkLength(%d, %v) = %v, want %v", tt.size, tt.duration, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package chunker
+
+import (
+	"math"
+	"strings"
+	"video-ai-stt/internal/stt"
+)
+
+// merge chunk 별 결과를 원본 기준 시간으로 옮기고, 겹치는 구간은 중간 지점을 경계로 나눔
+// 경계에 걸친 세그먼트는 단어 타임스탬프가 있으면 경계 안쪽 단어만 남기고, 없으면 앞 세그먼트와 겹치는 텍스트를 잘라냄
+func merge(chunks []Chunk, transcripts []*stt.Transcript) *stt.Transcript {
+	merged := &stt.Transcript{}
+
+	for i, chunk := range chunks {
+		transcript := transcripts[i]
+		if i == 0 {
+			merged.Provider = transcript.Provider
+			merged.Model = transcript.Model
+			merged.Language = transcript.Language
+		}
+
+		lower, upper := math.Inf(-1), math.Inf(1)
+		if i > 0 {
+			lower = (chunk.Start + chunks[i-1].End) / 2
+		}
+		if i < len(chunks)-1 {
+			upper = (chunks[i+1].Start + chunk.End) / 2
+		}
+
+		words := make([]stt.Word, 0, len(transcript.Words))
+		for _, word := range transcript.Words {
+			word.Start += chunk.Start
+			word.End += chunk.Start
+			words = append(words, word)
+			if word.Start >= lower && word.Start < upper {
+				merged.Words = append(merged.Words, word)
+			}
+		}
+
+		for _, segment := range transcript.Segments {
+			segment.Start += chunk.Start
+			segment.End += chunk.Start
+
+			// 담당 구간과 겹치지 않는 세그먼트는 인접 chunk 의 결과를 사용
+			if segment.End <= lower || segment.Start >= upper {
+				continue
+			}
+
+			if segment.Start < lower || segment.End > upper {
+				if len(words) > 0 {
+					var ok bool
+					if segment, ok = clipSegment(segment, words, lower, upper); !ok {
+						continue
+					}
+				} else if segment.Start < lower && len(merged.Segments) > 0 {
+					prev := merged.Segments[len(merged.Segments)-1]
+					segment.Text = trimOverlapText(prev.Text, segment.Text)
+				}
+			}
+			if strings.TrimSpace(segment.Text) == "" {
+				continue
+			}
+
+			if n := len(merged.Segments); n > 0 && segment.Start < merged.Segments[n-1].End {
+				segment.Start = math.Min(merged.Segments[n-1].End, segment.End)
+			}
+
+			segment.ID = int64(len(merged.Segments))
+			merged.Segments = append(merged.Segments, segment)
+		}
+	}
+
+	texts := make([]string, 0, len(merged.Segments))
+	for _, segment := range merged.Segments {
+		texts = append(texts, strings.TrimSpace(segment.Text))
+	}
+	merged.Text = strings.Join(texts, " ")
+
+	return merged
+}
+
+// clipSegment 경계에 걸친 세그먼트를 [lower, upper) 에서 시작하는 단어만으로 다시 구성, 남는 단어가 없으면 false
+func clipSegment(segment stt.Segment, words []stt.Word, lower, upper float64) (stt.Segment, bool) {
+	var kept []stt.Word
+	for _, word := range words {
+		middle := (word.Start + word.End) / 2
+		if middle < segment.Start || middle > segment.End {
+			continue
+		}
+		if word.Start >= lower && word.Start < upper {
+			kept = append(kept, word)
+		}
+	}
+	if len(kept) == 0 {
+		return segment, false
+	}
+
+	texts := make([]string, 0, len(kept))
+	for _, word := range kept {
+		texts = append(texts, strings.TrimSpace(word.Word))
+	}
+	segment.Text = strings.Join(texts, " ")
+	segment.Start = math.Max(segment.Start, kept[0].Start)
+	segment.End = math.Min(segment.End, kept[len(kept)-1].End)
+	return segment, true
+}
+
+// trimOverlapText 경계에서 이전 세그먼트의 끝과 겹치는 다음 세그먼트의 앞부분 단어 제거
+func trimOverlapText(prev, next string) string {
+	prevWords := strings.Fields(prev)
+	nextWords := strings.Fields(next)
+
+	maxOverlap := len(prevWords)
+	if len(nextWords) < maxOverlap {
+		maxOverlap = len(nextWords)
+	}
+
+	for n := maxOverlap; n > 0; n-- {
+		if equalWords(prevWords[len(prevWords)-n:], nextWords[:n]) {
+			return strings.Join(nextWords[n:], " ")
+		}
+	}
+	return next
+}
+
+func equalWords(a, b []string) bool {
+	for i := range a {
+		if normalizeWord(a[i]) != normalizeWord(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func normalizeWord(word string) string {
+	return strings.ToLower(strings.Trim(word, ".,!?;:\"'…"))
+}
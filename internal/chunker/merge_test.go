@@ -0,0 +1,175 @@
+package chunker
+
+import (
+	"testing"
+	"video-ai-stt/internal/stt"
+)
+
+// overlapChunks 595 초를 경계로 10 초 겹치는 두 chunk
+var overlapChunks = []Chunk{
+	{Index: 0, Start: 0, End: 600},
+	{Index: 1, Start: 590, End: 1200},
+}
+
+func segmentTexts(transcript *stt.Transcript) []string {
+	texts := make([]string, 0, len(transcript.Segments))
+	for _, segment := range transcript.Segments {
+		texts = append(texts, segment.Text)
+	}
+	return texts
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestMergeStraddlingSegmentByWords(t *testing.T) {
+	first := &stt.Transcript{
+		Segments: []stt.Segment{
+			{Start: 580, End: 587, Text: "hello there"},
+			{Start: 588, End: 598, Text: "my friend how are you"},
+		},
+		Words: []stt.Word{
+			{Word: "hello", Start: 580, End: 582},
+			{Word: "there", Start: 582, End: 587},
+			{Word: "my", Start: 588, End: 589},
+			{Word: "friend", Start: 589, End: 590.5},
+			{Word: "how", Start: 595.5, End: 596},
+			{Word: "are", Start: 596, End: 597},
+			{Word: "you", Start: 597, End: 598},
+		},
+	}
+	// 두 번째 chunk 는 590 초 기준 상대 시간
+	second := &stt.Transcript{
+		Segments: []stt.Segment{
+			{Start: 0, End: 8, Text: "my friend how are you"},
+			{Start: 9, End: 12, Text: "fine thanks"},
+		},
+		Words: []stt.Word{
+			{Word: "my", Start: 0, End: 1},
+			{Word: "friend", Start: 1, End: 2.5},
+			{Word: "how", Start: 5.5, End: 6},
+			{Word: "are", Start: 6, End: 7},
+			{Word: "you", Start: 7, End: 8},
+			{Word: "fine", Start: 9, End: 10},
+			{Word: "thanks", Start: 10, End: 12},
+		},
+	}
+
+	merged := merge(overlapChunks, []*stt.Transcript{first, second})
+
+	want := []string{"hello there", "my friend", "how are you", "fine thanks"}
+	if got := segmentTexts(merged); !equalStrings(got, want) {
+		t.Fatalf("segments = %q, want %q", got, want)
+	}
+	if merged.Text != "hello there my friend how are you fine thanks" {
+		t.Errorf("text = %q", merged.Text)
+	}
+	if len(merged.Words) != 9 {
+		t.Errorf("word count = %d, want 9", len(merged.Words))
+	}
+	if segment := merged.Segments[2]; segment.Start != 595.5 || segment.End != 598 {
+		t.Errorf("clipped segment = %v-%v, want 595.5-598", segment.Start, segment.End)
+	}
+	for i, segment := range merged.Segments {
+		if segment.ID != int64(i) {
+			t.Errorf("segment %d id = %d", i, segment.ID)
+		}
+		if i > 0 && segment.Start < merged.Segments[i-1].End {
+			t.Errorf("segment %d starts %v before previous end %v", i, segment.Start, merged.Segments[i-1].End)
+		}
+	}
+}
+
+func TestMergeStraddlingSegmentByText(t *testing.T) {
+	first := &stt.Transcript{
+		Segments: []stt.Segment{
+			{Start: 580, End: 587, Text: "hello there"},
+			{Start: 588, End: 598, Text: "my friend how are you."},
+		},
+	}
+	second := &stt.Transcript{
+		Segments: []stt.Segment{
+			{Start: 0, End: 10, Text: "friend, how are you? fine"},
+			{Start: 11, End: 13, Text: "thanks"},
+		},
+	}
+
+	merged := merge(overlapChunks, []*stt.Transcript{first, second})
+
+	want := []string{"hello there", "my friend how are you.", "fine", "thanks"}
+	if got := segmentTexts(merged); !equalStrings(got, want) {
+		t.Fatalf("segments = %q, want %q", got, want)
+	}
+	if segment := merged.Segments[2]; segment.Start != 598 {
+		t.Errorf("trimmed segment start = %v, want 598", segment.Start)
+	}
+}
+
+func TestMergeDropsDuplicatedSegmentByText(t *testing.T) {
+	first := &stt.Transcript{
+		Segments: []stt.Segment{{Start: 588, End: 598, Text: "my friend how are you"}},
+	}
+	second := &stt.Transcript{
+		Segments: []stt.Segment{
+			{Start: 0, End: 8, Text: "My friend, how are you"},
+			{Start: 9, End: 12, Text: "fine thanks"},
+		},
+	}
+
+	merged := merge(overlapChunks, []*stt.Transcript{first, second})
+
+	want := []string{"my friend how are you", "fine thanks"}
+	if got := segmentTexts(merged); !equalStrings(got, want) {
+		t.Fatalf("segments = %q, want %q", got, want)
+	}
+}
+
+func TestMergeSilenceCut(t *testing.T) {
+	chunks := []Chunk{
+		{Index: 0, Start: 0, End: 31},
+		{Index: 1, Start: 31, End: 60},
+	}
+	first := &stt.Transcript{
+		Provider: "groq",
+		Segments: []stt.Segment{{Start: 1, End: 29, Text: "first"}},
+	}
+	second := &stt.Transcript{
+		Segments: []stt.Segment{{Start: 1, End: 20, Text: "second"}},
+	}
+
+	merged := merge(chunks, []*stt.Transcript{first, second})
+
+	want := []string{"first", "second"}
+	if got := segmentTexts(merged); !equalStrings(got, want) {
+		t.Fatalf("segments = %q, want %q", got, want)
+	}
+	if merged.Segments[1].Start != 32 || merged.Provider != "groq" {
+		t.Errorf("merged = %+v", merged)
+	}
+}
+
+func TestTrimOverlapText(t *testing.T) {
+	tests := []struct {
+		prev, next, want string
+	}{
+		{"how are you", "are you fine", "fine"},
+		{"how are you.", "How are you? fine", "fine"},
+		{"hello", "world", "world"},
+		{"same text", "same text", ""},
+	}
+
+	for _, tt := range tests {
+		if got := trimOverlapText(tt.prev, tt.next); got != tt.want {
+			t.Errorf("trimOverlapText(%q, %q) = %q, want %q", tt.prev, tt.next, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package chunker
+
+import "video-ai-stt/internal/media"
+
+// Chunk 원본 오디오 기준 [Start, End) 구간(초), 무음에서 자르지 못한 경우 인접 chunk 와 overlap 만큼 겹침
+type Chunk struct {
+	Index int
+	Start float64
+	End   float64
+	Path  string
+}
+
+// plan 최대 length 길이의 구간으로 분할
+// 구간 후반부에 무음이 있으면 무음 중간에서 겹침 없이 자르고, 없으면 고정 길이로 자른 뒤 overlap 만큼 겹쳐서 시작
+// length 가 0 이하이면 나누지 않음
+func plan(duration, length, overlap float64, silences []media.Silence) []Chunk {
+	if length <= 0 {
+		return []Chunk{{Index: 0, Start: 0, End: duration}}
+	}
+	if length <= overlap {
+		overlap = 0
+	}
+
+	var chunks []Chunk
+	start := 0.0
+	for {
+		end := start + length
+		if end >= duration {
+			return append(chunks, Chunk{Index: len(chunks), Start: start, End: duration})
+		}
+
+		if cut, ok := findCut(silences, start+length/2, end); ok {
+			chunks = append(chunks, Chunk{Index: len(chunks), Start: start, End: cut})
+			start = cut
+			continue
+		}
+
+		chunks = append(chunks, Chunk{Index: len(chunks), Start: start, End: end})
+		start = end - overlap
+	}
+}
+
+// findCut (from, to] 범위에 중간 지점이 있는 무음 중 가장 뒤쪽 무음의 중간 지점
+func findCut(silences []media.Silence, from, to float64) (float64, bool) {
+	cut, found := 0.0, false
+	for _, silence := range silences {
+		middle := silence.Middle()
+		if middle > from && middle <= to {
+			cut, found = middle, true
+		}
+	}
+	return cut, found
+}
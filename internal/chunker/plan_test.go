@@ -0,0 +1,89 @@
+package chunker
+
+import (
+	"testing"
+	"video-ai-stt/internal/media"
+)
+
+func TestPlan(t *testing.T) {
+	tests := []struct {
+		name     string
+		duration float64
+		silences []media.Silence
+		want     []Chunk
+	}{
+		{
+			name:     "short audio",
+			duration: 30,
+			want:     []Chunk{{Index: 0, Start: 0, End: 30}},
+		},
+		{
+			name:     "fixed length with overlap",
+			duration: 100,
+			want: []Chunk{
+				{Index: 0, Start: 0, End: 40},
+				{Index: 1, Start: 35, End: 75},
+				{Index: 2, Start: 70, End: 100},
+			},
+		},
+		{
+			name:     "cut at silence without overlap",
+			duration: 100,
+			silences: []media.Silence{{Start: 30, End: 32}},
+			want: []Chunk{
+				{Index: 0, Start: 0, End: 31},
+				{Index: 1, Start: 31, End: 71},
+				{Index: 2, Start: 66, End: 100},
+			},
+		},
+		{
+			name:     "ignore silence in first half",
+			duration: 60,
+			silences: []media.Silence{{Start: 5, End: 7}},
+			want: []Chunk{
+				{Index: 0, Start: 0, End: 40},
+				{Index: 1, Start: 35, End: 60},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := plan(tt.duration, 40, 5, tt.silences)
+			if len(got) != len(tt.want) {
+				t.Fatalf("plan() = %+v, want %+v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("chunk %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestPlanOverlapNotShorterThanLength(t *testing.T) {
+	got := plan(25, 10, 10, nil)
+	want := []Chunk{
+		{Index: 0, Start: 0, End: 10},
+		{Index: 1, Start: 10, End: 20},
+		{Index: 2, Start: 20, End: 25},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("plan() = %+v, want %+v", got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Errorf("chunk %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestPlanNonPositiveLength(t *testing.T) {
+	for _, length := range []float64{0, -1} {
+		got := plan(100, length, 5, nil)
+		if len(got) != 1 || got[0].Start != 0 || got[0].End != 100 {
+			t.Errorf("plan(length=%v) = %+v, want single chunk", length, got)
+		}
+	}
+}
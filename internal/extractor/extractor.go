@@ -1,109 +1,250 @@
 package extractor
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
 	"os"
 	"path/filepath"
 	"strings"
-	"sync"
+	"time"
 	"video-ai-stt/config"
+	"video-ai-stt/internal/failure"
 	"video-ai-stt/internal/job"
+	"video-ai-stt/internal/media"
+	"video-ai-stt/internal/pipeline"
 	"video-ai-stt/internal/process"
 )
 
 type Extractor struct {
 	cfg       config.Extractor
 	processed *process.ProcessedManager
+	failure   *failure.Recorder
 }
 
-func NewExtractor(cfg config.Extractor, manager *process.ProcessedManager) *Extractor {
+func NewExtractor(cfg config.Extractor, manager *process.ProcessedManager, recorder *failure.Recorder) *Extractor {
 	return &Extractor{
 		cfg:       cfg,
 		processed: manager,
+		failure:   recorder,
 	}
 }
 
 func (e *Extractor) Process(ctx context.Context, videoCh <-chan *job.Job, audioCh chan<- *job.Job) error {
 
-	wg := sync.WaitGroup{}
+	pool := pipeline.NewPool(e.cfg.Concurrency)
 
 LOOP:
 	for {
+		// 실행 자리가 생길 때까지 videoCh 를 읽지 않음
+		if !pool.Acquire(ctx) {
+			slog.Debug("extract goroutine close")
+			break LOOP
+		}
+
 		select {
 		case <-ctx.Done():
+			pool.Release()
 			slog.Debug("extract goroutine close")
 			break LOOP
 
 		case jobs, ok := <-videoCh:
 			if !ok {
+				pool.Release()
 				slog.Debug("extractor videoCh closed, breaking loop")
 				break LOOP
 			}
 
 			alreadyProcess := e.processed.IsProcessed(jobs.GetVideoPath(), process.EXTRACT_AUDIO_START)
 			if alreadyProcess {
+				pool.Release()
 				continue
 			}
 
-			wg.Add(1)
-			go func(jobs *job.Job) {
-				defer wg.Done()
+			queueDepth := len(videoCh)
+			pool.Go(func() {
+				jobCtx, cancel := e.processed.JobContext(ctx, jobs.GetRID())
+				defer cancel()
 
-				e.processed.MarkProcessed(jobs.GetVideoPath(), process.EXTRACT_AUDIO_START)
 				logger := slog.With("rid", jobs.GetRID(), "video_path", jobs.GetVideoPath())
-				logger.Info("start audio extractor goroutine", "step", process.EXTRACT_AUDIO_START)
+				if !e.processed.MarkProcessed(jobs, process.EXTRACT_AUDIO_START) {
+					logger.Info("skip audio extractor, job cancelled or finished", "step", process.EXTRACT_AUDIO_START)
+					return
+				}
+				logger.Info("start audio extractor goroutine", "step", process.EXTRACT_AUDIO_START, "queue_depth", queueDepth, "running", pool.Running())
 
-				audioPath, err := e.extractAudio(jobs)
+				info, err := e.probe(jobCtx, jobs)
 				if err != nil {
-					slog.Error("failed extract audio ffmpeg", "err", err.Error())
+					if e.processed.Interrupted(ctx, jobs, process.WATCHER_FILE_REGISTER) {
+						return
+					}
+					logger.Error("failed probe media", "err", err.Error(), "step", process.PROBE_MEDIA_FAILED)
+					e.failure.Fail(jobs, process.PROBE_MEDIA_FAILED, err)
+					return
+				}
+				jobs.SetMedia(info)
+
+				if e.isPassthrough(jobs) {
+					logger.Info("audio only input, skip extraction", "format_name", info.FormatName, "codec", info.AudioStreams[0].CodecName)
+					jobs.SetAudioPath(jobs.GetVideoPath())
+				} else {
+					audioPath, err := e.extractAudio(jobCtx, jobs)
+					if err != nil {
+						if e.processed.Interrupted(ctx, jobs, process.WATCHER_FILE_REGISTER) {
+							return
+						}
+						logger.Error("failed extract audio ffmpeg", "err", err.Error(), "step", process.EXTRACT_AUDIO_FAILED)
+						e.failure.Fail(jobs, process.EXTRACT_AUDIO_FAILED, err)
+						return
+					}
+					jobs.SetAudioPath(audioPath)
+				}
+
+				if e.cfg.SilenceDetect {
+					silences, err := e.detectSilence(jobCtx, jobs)
+					if err != nil {
+						if e.processed.Interrupted(ctx, jobs, process.WATCHER_FILE_REGISTER) {
+							return
+						}
+						logger.Warn("failed detect silence, continue without silence map", "err", err.Error())
+					} else {
+						jobs.SetSilences(silences)
+						logger.Debug("detect silence", "silence_count", len(silences))
+					}
+				}
+
+				if !e.processed.MarkProcessed(jobs, process.EXTRACT_AUDIO_COMPLETE) {
+					logger.Info("job cancelled during audio extractor", "step", process.EXTRACT_AUDIO_COMPLETE)
 					return
 				}
+				logger.Info("end audio extractor goroutine", "audio_path", jobs.GetAudioPath(), "step", process.EXTRACT_AUDIO_COMPLETE, "audio_queue_depth", len(audioCh))
 
-				jobs.SetAudioPath(audioPath)
-				e.processed.MarkProcessed(jobs.GetVideoPath(), process.EXTRACT_AUDIO_COMPLETE)
-				logger.Info("end audio extractor goroutine", "audio_path", jobs.GetAudioPath(), "step", process.EXTRACT_AUDIO_COMPLETE)
-				audioCh <- jobs
-			}(jobs)
+				select {
+				case audioCh <- jobs:
+				case <-ctx.Done():
+					// 추출 완료 상태로 남겨 재시작 시 추출된 오디오부터 이어서 처리
+					logger.Warn("audio queue closed by shutdown, resume after restart", "step", process.EXTRACT_AUDIO_COMPLETE)
+				}
+			})
 		}
 	}
 
 	slog.Debug("waiting for all extract goroutines to finish")
-	wg.Wait()
+	pool.Wait()
 	slog.Debug("all extractor goroutines completed")
 
+	// 남은 작업을 모두 넘긴 뒤 stt 단계에 종료를 알림
+	close(audioCh)
+
 	return nil
 }
 
-func (e *Extractor) extractAudio(jobs *job.Job) (string, error) {
+// probe 컨테이너와 스트림 정보를 확인, 오디오 스트림이 없으면 ErrNoAudioStream
+func (e *Extractor) probe(ctx context.Context, jobs *job.Job) (*media.Info, error) {
+	ctx, cancel := pipeline.WithTimeout(ctx, e.cfg.ProbeTimeout)
+	defer cancel()
+
+	info, err := ProbeMedia(ctx, jobs.GetVideoPath())
+	if errors.Is(err, ErrInvalidMedia) {
+		return nil, failure.NotMedia(err)
+	}
+	if err != nil {
+		return nil, failure.Timeout(ctx, e.cfg.ProbeTimeout, err)
+	}
+
+	slog.Info("probe media", "rid", jobs.GetRID(), "video_path", jobs.GetVideoPath(), "format_name", info.FormatName, "duration", info.Duration, "bit_rate", info.BitRate, "video_stream_count", len(info.VideoStreams), "audio_stream_count", len(info.AudioStreams), "languages", info.Languages())
+
+	if !info.HasAudio() {
+		return nil, failure.NotMedia(fmt.Errorf("%w, format: %s, video stream count: %d", ErrNoAudioStream, info.FormatName, len(info.VideoStreams)))
+	}
+	return info, nil
+}
+
+// isPassthrough 오디오만 있는 입력이면서 공급자가 그대로 받을 수 있는 형식인지 확인
+func (e *Extractor) isPassthrough(jobs *job.Job) bool {
+	if !jobs.GetMedia().IsAudioOnly() {
+		return false
+	}
+
+	ext := strings.ToLower(filepath.Ext(jobs.GetVideoPath()))
+	for _, passthrough := range e.cfg.AudioPassthrough {
+		if ext == strings.ToLower(passthrough) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *Extractor) extractAudio(ctx context.Context, jobs *job.Job) (string, error) {
 
-	filename := filepath.Base(jobs.GetVideoPath())
-	outputPath := e.changeExtOutputPath(filepath.Join(e.cfg.OutputDir, filename))
+	timeout := e.extractTimeout(jobs.GetMedia())
+	ctx, cancel := pipeline.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	outputPath := filepath.Join(e.cfg.OutputDir, jobs.OutputName()+e.cfg.OutputFormat)
+
+	// 오디오 트랙이 여러 개인 경우 업로드 시 또는 프로필의 언어 힌트와 맞는 트랙을 우선 사용
+	stream, _ := jobs.GetMedia().PrimaryAudio(jobs.GetLanguage())
+
+	sampleRate := e.cfg.OutputSampleRate
+	if profile := jobs.GetProfile(); profile.SampleRate != "" {
+		sampleRate = profile.SampleRate
+	}
 
 	cmd := NewFFmpegBuilder().
 		Input(jobs.GetVideoPath()).
-		AudioSampleRate(e.cfg.OutputSampleRate).
+		AudioSampleRate(sampleRate).
 		AudioChannels(1).
-		MapAudio().
+		MapStream(stream.Index).
 		UseFlacCodec().
 		Output(outputPath).
-		Build()
+		Build(ctx)
 
-	slog.Debug("exec cmd ffmpeg", "cmd", strings.Join(cmd.Args, " "), "rid", jobs.GetRID(), "video_path", jobs.GetVideoPath(), "audio_path", jobs.GetAudioPath())
+	slog.Debug("exec cmd ffmpeg", "cmd", strings.Join(cmd.Args, " "), "rid", jobs.GetRID(), "video_path", jobs.GetVideoPath(), "audio_path", jobs.GetAudioPath(), "timeout", timeout.String())
 
 	// 표준 출력 및 오류 출력 설정
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 
 	if err := cmd.Run(); err != nil {
-		return "", err
+		return "", failure.Timeout(ctx, timeout, err)
 	}
 
 	return outputPath, nil
 }
 
-func (e *Extractor) changeExtOutputPath(outputPath string) string {
-	ext := filepath.Ext(outputPath)
-	base := strings.TrimSuffix(outputPath, ext)
-	return base + e.cfg.OutputFormat
+// detectSilence 추출된 오디오의 무음 구간 맵 계산
+func (e *Extractor) detectSilence(ctx context.Context, jobs *job.Job) ([]media.Silence, error) {
+
+	timeout := e.extractTimeout(jobs.GetMedia())
+	ctx, cancel := pipeline.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := NewFFmpegBuilder().
+		Input(jobs.GetAudioPath()).
+		SilenceDetect(e.cfg.SilenceNoise, e.cfg.SilenceMinDuration).
+		NullOutput().
+		Build(ctx)
+
+	slog.Debug("exec cmd ffmpeg", "cmd", strings.Join(cmd.Args, " "), "rid", jobs.GetRID(), "audio_path", jobs.GetAudioPath())
+
+	// silencedetect 결과는 stderr 로 출력됨
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, failure.Timeout(ctx, timeout, fmt.Errorf("failed silencedetect ffmpeg, err: %w", err))
+	}
+
+	return ParseSilenceDetect(&stderr)
+}
+
+// extractTimeout STT_EXTRACT_TIMEOUT + 미디어 길이 × STT_EXTRACT_TIMEOUT_RATIO, STT_EXTRACT_TIMEOUT 이 0 이면 제한 없음
+func (e *Extractor) extractTimeout(info *media.Info) time.Duration {
+	if e.cfg.Timeout <= 0 || info == nil {
+		return e.cfg.Timeout
+	}
+	return e.cfg.Timeout + time.Duration(info.Duration*e.cfg.TimeoutRatio*float64(time.Second))
 }
@@ -1,10 +1,17 @@
 package extractor
 
 import (
+	"context"
+	"fmt"
+	"os"
 	"os/exec"
 	"strconv"
+	"time"
 )
 
+// STOP_GRACE_PERIOD ctx 종료 시 SIGINT 로 정리할 시간을 준 뒤 강제 종료(SIGKILL)하기까지의 대기 시간
+const STOP_GRACE_PERIOD = 5 * time.Second
+
 type FFmpegBuilder struct {
 	args []string
 }
@@ -20,6 +27,17 @@ func (b *FFmpegBuilder) Input(inputPath string) *FFmpegBuilder {
 	return b
 }
 
+// Seek Input 앞에 지정하면 입력 파일의 해당 위치(초)부터 읽음
+func (b *FFmpegBuilder) Seek(seconds float64) *FFmpegBuilder {
+	b.args = append(b.args, "-ss", strconv.FormatFloat(seconds, 'f', 3, 64))
+	return b
+}
+
+func (b *FFmpegBuilder) Duration(seconds float64) *FFmpegBuilder {
+	b.args = append(b.args, "-t", strconv.FormatFloat(seconds, 'f', 3, 64))
+	return b
+}
+
 func (b *FFmpegBuilder) Output(outputPath string) *FFmpegBuilder {
 	b.args = append(b.args, outputPath)
 	return b
@@ -40,11 +58,39 @@ func (b *FFmpegBuilder) MapAudio() *FFmpegBuilder {
 	return b
 }
 
+// MapStream 입력 파일의 특정 스트림만 사용 (-map 0:<index>)
+func (b *FFmpegBuilder) MapStream(index int) *FFmpegBuilder {
+	b.args = append(b.args, "-map", fmt.Sprintf("0:%d", index))
+	return b
+}
+
 func (b *FFmpegBuilder) UseFlacCodec() *FFmpegBuilder {
 	b.args = append(b.args, "-c:a", "flac")
 	return b
 }
 
-func (b *FFmpegBuilder) Build() *exec.Cmd {
-	return exec.Command("ffmpeg", b.args...)
+// SilenceDetect noise(dB) 이하의 소리가 minDuration(초) 이상 지속되는 구간을 stderr 로 출력
+func (b *FFmpegBuilder) SilenceDetect(noise string, minDuration float64) *FFmpegBuilder {
+	b.args = append(b.args, "-af", fmt.Sprintf("silencedetect=noise=%s:d=%s", noise, strconv.FormatFloat(minDuration, 'f', -1, 64)))
+	return b
+}
+
+// NullOutput 분석 전용 실행 시 결과 파일을 만들지 않음
+func (b *FFmpegBuilder) NullOutput() *FFmpegBuilder {
+	b.args = append(b.args, "-f", "null", "-")
+	return b
+}
+
+// Build ctx 가 종료되면 ffmpeg 에 SIGINT 를 보내고 STOP_GRACE_PERIOD 이후에도 끝나지 않으면 강제 종료
+func (b *FFmpegBuilder) Build(ctx context.Context) *exec.Cmd {
+	return commandContext(ctx, "ffmpeg", b.args...)
+}
+
+func commandContext(ctx context.Context, name string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Cancel = func() error {
+		return cmd.Process.Signal(os.Interrupt)
+	}
+	cmd.WaitDelay = STOP_GRACE_PERIOD
+	return cmd
 }
@@ -0,0 +1,68 @@
+package extractor
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+type FFprobeBuilder struct {
+	args []string
+}
+
+func NewFFprobeBuilder() *FFprobeBuilder {
+	return &FFprobeBuilder{
+		args: []string{"-v", "error"},
+	}
+}
+
+func (b *FFprobeBuilder) ShowEntries(entries string) *FFprobeBuilder {
+	b.args = append(b.args, "-show_entries", entries)
+	return b
+}
+
+func (b *FFprobeBuilder) ShowFormat() *FFprobeBuilder {
+	b.args = append(b.args, "-show_format")
+	return b
+}
+
+func (b *FFprobeBuilder) ShowStreams() *FFprobeBuilder {
+	b.args = append(b.args, "-show_streams")
+	return b
+}
+
+func (b *FFprobeBuilder) OutputFormat(format string) *FFprobeBuilder {
+	b.args = append(b.args, "-of", format)
+	return b
+}
+
+func (b *FFprobeBuilder) Input(inputPath string) *FFprobeBuilder {
+	b.args = append(b.args, inputPath)
+	return b
+}
+
+func (b *FFprobeBuilder) Build(ctx context.Context) *exec.Cmd {
+	return commandContext(ctx, "ffprobe", b.args...)
+}
+
+// ProbeDuration 미디어 파일의 재생 시간(초)
+func ProbeDuration(ctx context.Context, inputPath string) (float64, error) {
+	cmd := NewFFprobeBuilder().
+		ShowEntries("format=duration").
+		OutputFormat("default=noprint_wrappers=1:nokey=1").
+		Input(inputPath).
+		Build(ctx)
+
+	output, err := cmd.Output()
+	if err != nil {
+		return 0, fmt.Errorf("failed ffprobe duration, path: %s, err: %w", inputPath, err)
+	}
+
+	duration, err := strconv.ParseFloat(strings.TrimSpace(string(output)), 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed parsing ffprobe duration, output: %s, err: %w", string(output), err)
+	}
+	return duration, nil
+}
@@ -0,0 +1,115 @@
+package extractor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+	"video-ai-stt/internal/media"
+)
+
+var (
+	ErrInvalidMedia  = errors.New("invalid media file")
+	ErrNoAudioStream = errors.New("no audio stream in media file")
+)
+
+type probeOutput struct {
+	Format struct {
+		FormatName string `json:"format_name"`
+		Duration   string `json:"duration"`
+		Size       string `json:"size"`
+		BitRate    string `json:"bit_rate"`
+	} `json:"format"`
+	Streams []struct {
+		Index       int               `json:"index"`
+		CodecType   string            `json:"codec_type"`
+		CodecName   string            `json:"codec_name"`
+		BitRate     string            `json:"bit_rate"`
+		SampleRate  string            `json:"sample_rate"`
+		Channels    int               `json:"channels"`
+		Duration    string            `json:"duration"`
+		Tags        map[string]string `json:"tags"`
+		Disposition map[string]int    `json:"disposition"`
+	} `json:"streams"`
+}
+
+// ProbeMedia ffprobe 로 컨테이너와 스트림을 확인, 미디어가 아닌 파일은 ErrInvalidMedia
+func ProbeMedia(ctx context.Context, inputPath string) (*media.Info, error) {
+	cmd := NewFFprobeBuilder().
+		ShowFormat().
+		ShowStreams().
+		OutputFormat("json").
+		Input(inputPath).
+		Build(ctx)
+
+	output, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && ctx.Err() == nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidMedia, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, fmt.Errorf("failed ffprobe media, path: %s, err: %w", inputPath, err)
+	}
+
+	return ParseProbe(bytes.NewReader(output))
+}
+
+// ParseProbe ffprobe -show_format -show_streams -of json 출력 파싱
+func ParseProbe(r io.Reader) (*media.Info, error) {
+	output := probeOutput{}
+	if err := json.NewDecoder(r).Decode(&output); err != nil {
+		return nil, fmt.Errorf("failed parsing ffprobe output: %w", err)
+	}
+
+	if output.Format.FormatName == "" || len(output.Streams) == 0 {
+		return nil, fmt.Errorf("%w: no streams", ErrInvalidMedia)
+	}
+
+	info := &media.Info{
+		FormatName: output.Format.FormatName,
+		Duration:   parseFloat(output.Format.Duration),
+		Size:       parseInt(output.Format.Size),
+		BitRate:    parseInt(output.Format.BitRate),
+	}
+
+	for _, s := range output.Streams {
+		stream := media.Stream{
+			Index:      s.Index,
+			CodecType:  s.CodecType,
+			CodecName:  s.CodecName,
+			Language:   s.Tags["language"],
+			BitRate:    parseInt(s.BitRate),
+			SampleRate: int(parseInt(s.SampleRate)),
+			Channels:   s.Channels,
+			Duration:   parseFloat(s.Duration),
+			Default:    s.Disposition["default"] == 1,
+		}
+
+		switch s.CodecType {
+		case media.CODEC_TYPE_VIDEO:
+			// 음원 파일의 앨범 커버는 영상 스트림으로 보지 않음
+			if s.Disposition["attached_pic"] == 1 {
+				continue
+			}
+			info.VideoStreams = append(info.VideoStreams, stream)
+		case media.CODEC_TYPE_AUDIO:
+			info.AudioStreams = append(info.AudioStreams, stream)
+		}
+	}
+	return info, nil
+}
+
+func parseFloat(value string) float64 {
+	f, _ := strconv.ParseFloat(value, 64)
+	return f
+}
+
+func parseInt(value string) int64 {
+	i, _ := strconv.ParseInt(value, 10, 64)
+	return i
+}
@@ -0,0 +1,109 @@
+package extractor
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const probeVideoWithTracks = `{
+  "streams": [
+    {"index": 0, "codec_name": "h264", "codec_type": "video", "disposition": {"default": 1, "attached_pic": 0}},
+    {"index": 1, "codec_name": "aac", "codec_type": "audio", "sample_rate": "48000", "channels": 2, "bit_rate": "128000", "duration": "60.000000",
+     "disposition": {"default": 0}, "tags": {"language": "eng"}},
+    {"index": 2, "codec_name": "aac", "codec_type": "audio", "sample_rate": "44100", "channels": 1,
+     "disposition": {"default": 1}, "tags": {"language": "kor"}},
+    {"index": 3, "codec_name": "mov_text", "codec_type": "subtitle"}
+  ],
+  "format": {"format_name": "mov,mp4,m4a,3gp,3g2,mj2", "duration": "60.021000", "size": "1048576", "bit_rate": "139810"}
+}`
+
+const probeAudioWithCover = `{
+  "streams": [
+    {"index": 0, "codec_name": "mp3", "codec_type": "audio", "sample_rate": "44100", "channels": 2},
+    {"index": 1, "codec_name": "mjpeg", "codec_type": "video", "disposition": {"attached_pic": 1}}
+  ],
+  "format": {"format_name": "mp3", "duration": "180.5"}
+}`
+
+const probeVideoWithoutAudio = `{
+  "streams": [
+    {"index": 0, "codec_name": "h264", "codec_type": "video", "disposition": {"default": 1}}
+  ],
+  "format": {"format_name": "matroska,webm", "duration": "12.5"}
+}`
+
+func TestParseProbe(t *testing.T) {
+	info, err := ParseProbe(strings.NewReader(probeVideoWithTracks))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if info.FormatName != "mov,mp4,m4a,3gp,3g2,mj2" || info.Duration != 60.021 || info.Size != 1048576 || info.BitRate != 139810 {
+		t.Errorf("format = %+v", info)
+	}
+	if len(info.VideoStreams) != 1 || len(info.AudioStreams) != 2 {
+		t.Fatalf("video streams = %d, audio streams = %d", len(info.VideoStreams), len(info.AudioStreams))
+	}
+
+	eng := info.AudioStreams[0]
+	if eng.Index != 1 || eng.Language != "eng" || eng.SampleRate != 48000 || eng.Channels != 2 || eng.BitRate != 128000 || eng.Duration != 60 || eng.Default {
+		t.Errorf("first audio stream = %+v", eng)
+	}
+	if kor := info.AudioStreams[1]; kor.Index != 2 || !kor.Default {
+		t.Errorf("second audio stream = %+v", kor)
+	}
+	if !info.HasAudio() || info.IsAudioOnly() {
+		t.Errorf("HasAudio = %v, IsAudioOnly = %v", info.HasAudio(), info.IsAudioOnly())
+	}
+	if stream, _ := info.PrimaryAudio("ko"); stream.Index != 2 {
+		t.Errorf("PrimaryAudio(ko) = %d, want 2", stream.Index)
+	}
+}
+
+func TestParseProbeAudioWithCover(t *testing.T) {
+	info, err := ParseProbe(strings.NewReader(probeAudioWithCover))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(info.VideoStreams) != 0 || !info.IsAudioOnly() {
+		t.Errorf("cover art counted as video: %+v", info)
+	}
+}
+
+func TestParseProbeWithoutAudio(t *testing.T) {
+	info, err := ParseProbe(strings.NewReader(probeVideoWithoutAudio))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.HasAudio() || len(info.VideoStreams) != 1 {
+		t.Errorf("info = %+v, want video only", info)
+	}
+	if _, ok := info.PrimaryAudio(""); ok {
+		t.Error("PrimaryAudio() found a stream in video without audio")
+	}
+}
+
+func TestParseProbeInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		invalid bool
+	}{
+		{name: "not json", output: "Invalid data found when processing input"},
+		{name: "no streams", output: `{"streams": [], "format": {"format_name": "tty"}}`, invalid: true},
+		{name: "no format", output: `{"streams": [{"index": 0, "codec_type": "audio"}], "format": {}}`, invalid: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseProbe(strings.NewReader(tt.output))
+			if err == nil {
+				t.Fatal("ParseProbe() accepted invalid output")
+			}
+			if errors.Is(err, ErrInvalidMedia) != tt.invalid {
+				t.Errorf("ParseProbe() err = %v, ErrInvalidMedia = %v", err, tt.invalid)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package extractor
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"video-ai-stt/internal/media"
+)
+
+var (
+	silenceStartRegex = regexp.MustCompile(`silence_start:\s*(-?[0-9.]+)`)
+	silenceEndRegex   = regexp.MustCompile(`silence_end:\s*(-?[0-9.]+)`)
+)
+
+// ParseSilenceDetect ffmpeg silencedetect 필터의 stderr 출력을 무음 구간 목록으로 변환
+//
+//	[silencedetect @ 0x...] silence_start: 12.345
+//	[silencedetect @ 0x...] silence_end: 13.789 | silence_duration: 1.444
+func ParseSilenceDetect(r io.Reader) ([]media.Silence, error) {
+	var silences []media.Silence
+	var start float64
+	opened := false
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if match := silenceStartRegex.FindStringSubmatch(line); match != nil {
+			value, err := strconv.ParseFloat(match[1], 64)
+			if err != nil {
+				return nil, fmt.Errorf("failed parsing silence_start, line: %s, err: %w", line, err)
+			}
+			start, opened = value, true
+			continue
+		}
+
+		if match := silenceEndRegex.FindStringSubmatch(line); match != nil && opened {
+			end, err := strconv.ParseFloat(match[1], 64)
+			if err != nil {
+				return nil, fmt.Errorf("failed parsing silence_end, line: %s, err: %w", line, err)
+			}
+			if start < 0 {
+				start = 0
+			}
+			silences = append(silences, media.Silence{Start: start, End: end})
+			opened = false
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed reading silencedetect output: %w", err)
+	}
+
+	// 끝이 닫히지 않은 마지막 무음 구간은 자를 위치로 쓸 수 없으므로 제외
+	return silences, nil
+}
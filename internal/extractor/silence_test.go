@@ -0,0 +1,75 @@
+package extractor
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"video-ai-stt/internal/media"
+)
+
+func TestParseSilenceDetect(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []media.Silence
+	}{
+		{
+			name: "closed silences",
+			output: `Input #0, flac, from 'a.flac':
+  Duration: 00:01:00.00, start: 0.000000, bitrate: 256 kb/s
+[silencedetect @ 0x55d5c8a0c440] silence_start: 12.345
+[silencedetect @ 0x55d5c8a0c440] silence_end: 13.789 | silence_duration: 1.444
+size=N/A time=00:00:30.00 bitrate=N/A speed= 600x
+[silencedetect @ 0x55d5c8a0c440] silence_start: 30.5
+[silencedetect @ 0x55d5c8a0c440] silence_end: 31 | silence_duration: 0.5
+`,
+			want: []media.Silence{{Start: 12.345, End: 13.789}, {Start: 30.5, End: 31}},
+		},
+		{
+			name: "trailing silence_start without end",
+			output: `[silencedetect @ 0x1] silence_start: 5
+[silencedetect @ 0x1] silence_end: 6.5 | silence_duration: 1.5
+[silencedetect @ 0x1] silence_start: 58.2
+size=N/A time=00:01:00.00 bitrate=N/A
+`,
+			want: []media.Silence{{Start: 5, End: 6.5}},
+		},
+		{
+			name: "negative start clamped",
+			output: `[silencedetect @ 0x1] silence_start: -0.0213
+[silencedetect @ 0x1] silence_end: 1.2 | silence_duration: 1.2213
+`,
+			want: []media.Silence{{Start: 0, End: 1.2}},
+		},
+		{
+			name: "end without start ignored",
+			output: `[silencedetect @ 0x1] silence_end: 1.2 | silence_duration: 1.2
+`,
+			want: nil,
+		},
+		{
+			name:   "no silence",
+			output: "size=N/A time=00:01:00.00 bitrate=N/A speed= 600x\n",
+			want:   nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSilenceDetect(strings.NewReader(tt.output))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseSilenceDetect() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSilenceDetectInvalidNumber(t *testing.T) {
+	output := "[silencedetect @ 0x1] silence_start: 1.2.3\n"
+	if _, err := ParseSilenceDetect(strings.NewReader(output)); err == nil {
+		t.Error("ParseSilenceDetect() accepted invalid silence_start")
+	}
+}
@@ -0,0 +1,160 @@
+package failure
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+	"video-ai-stt/config"
+	"video-ai-stt/internal/job"
+	"video-ai-stt/internal/process"
+)
+
+const (
+	// REASON_TIMEOUT 단계 제한 시간 초과로 실패한 작업의 실패 원인
+	REASON_TIMEOUT = "timeout"
+	// REASON_NOT_MEDIA 미디어가 아니거나 오디오가 없는 파일, 원본은 옮기지 않음
+	REASON_NOT_MEDIA = "not_media"
+)
+
+var (
+	ErrTimeout  = errors.New("stage timeout")
+	ErrNotMedia = errors.New("not media")
+)
+
+var stageNames = map[int]string{
+	process.EXTRACT_AUDIO_FAILED:     "extract_audio",
+	process.REQUEST_GROQ_API_FAILED:  "request_stt",
+	process.GENERATE_SUBTITLE_FAILED: "generate_subtitle",
+	process.PROBE_MEDIA_FAILED:       "probe_media",
+}
+
+// Report failed 디렉토리에 함께 저장되는 .error.json 내용
+type Report struct {
+	RID        string    `json:"rid"`
+	VideoPath  string    `json:"video_path"`
+	FailedPath string    `json:"failed_path,omitempty"`
+	AudioPath  string    `json:"audio_path,omitempty"`
+	Stage      string    `json:"stage"`
+	Step       int       `json:"step"`
+	Reason     string    `json:"reason,omitempty"`
+	Error      string    `json:"error"`
+	FailedAt   time.Time `json:"failed_at"`
+}
+
+type Recorder struct {
+	cfg       config.Failure
+	processed *process.ProcessedManager
+}
+
+func NewRecorder(cfg config.Failure, manager *process.ProcessedManager) *Recorder {
+	return &Recorder{
+		cfg:       cfg,
+		processed: manager,
+	}
+}
+
+// Fail 작업을 실패 상태로 기록하고 원본 영상을 failed 디렉토리로 이동
+// 미디어가 아닌 파일은 기록 중인 임시 파일일 수 있으므로 옮기지 않고 보고서만 남김
+func (r *Recorder) Fail(jobs *job.Job, step int, cause error) {
+	logger := slog.With("rid", jobs.GetRID(), "video_path", jobs.GetVideoPath(), "step", step)
+
+	jobs.SetError(cause.Error())
+	jobs.SetReason(reason(cause))
+
+	// 영상을 옮기기 전에 실패 상태를 먼저 기록, 그 사이 취소된 작업은 그대로 둠
+	if !r.processed.MarkProcessed(jobs, step) {
+		logger.Info("skip failure record, job cancelled or finished", "cause", cause.Error())
+		return
+	}
+
+	if err := os.MkdirAll(r.cfg.Dir, 0755); err != nil {
+		logger.Error("failed creating failed dir", "failed_dir", r.cfg.Dir, "error", err.Error())
+		return
+	}
+
+	failedPath := ""
+	if jobs.GetReason() != REASON_NOT_MEDIA {
+		failedPath = r.failedPath(jobs)
+		if err := os.Rename(jobs.GetVideoPath(), failedPath); err != nil {
+			logger.Error("failed moving video to failed dir, mark only", "failed_path", failedPath, "error", err.Error())
+			failedPath = ""
+		}
+	}
+	jobs.SetFailedPath(failedPath)
+
+	report := Report{
+		RID:        jobs.GetRID(),
+		VideoPath:  jobs.GetVideoPath(),
+		FailedPath: failedPath,
+		AudioPath:  jobs.GetAudioPath(),
+		Stage:      stageNames[step],
+		Step:       step,
+		Reason:     jobs.GetReason(),
+		Error:      cause.Error(),
+		FailedAt:   time.Now(),
+	}
+	if err := r.writeReport(jobs, report); err != nil {
+		logger.Error("failed writing error report", "error", err.Error())
+	}
+
+	r.processed.MarkProcessed(jobs, step)
+	logger.Error("job failed", "stage", report.Stage, "reason", report.Reason, "failed_path", failedPath, "cause", cause.Error())
+}
+
+// Timeout 단계 ctx 의 제한 시간이 지나 중단된 경우 err 를 ErrTimeout 으로 감싸 반환
+func Timeout(ctx context.Context, limit time.Duration, err error) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%w: exceeded %s: %w", ErrTimeout, limit, err)
+	}
+	return err
+}
+
+// NotMedia 미디어가 아닌 파일로 판단된 err 를 ErrNotMedia 로 감싸 반환
+func NotMedia(err error) error {
+	return fmt.Errorf("%w: %w", ErrNotMedia, err)
+}
+
+// reason 실패 원인 분류, 분류되지 않은 오류는 빈 값
+func reason(cause error) string {
+	switch {
+	case errors.Is(cause, ErrTimeout):
+		return REASON_TIMEOUT
+	case errors.Is(cause, ErrNotMedia):
+		return REASON_NOT_MEDIA
+	}
+	return ""
+}
+
+func (r *Recorder) failedPath(jobs *job.Job) string {
+	filename := filepath.Base(jobs.GetVideoPath())
+	path := filepath.Join(r.cfg.Dir, filename)
+	if _, err := os.Stat(path); err == nil {
+		path = filepath.Join(r.cfg.Dir, jobs.GetRID()+"_"+filename)
+	}
+	return path
+}
+
+func (r *Recorder) writeReport(jobs *job.Job, report Report) error {
+	reportPath := filepath.Join(r.cfg.Dir, filepath.Base(jobs.GetVideoPath())+".error.json")
+	if report.FailedPath != "" {
+		reportPath = report.FailedPath + ".error.json"
+	}
+
+	file, err := os.Create(reportPath)
+	if err != nil {
+		return fmt.Errorf("failed creating report file: %w", err)
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return fmt.Errorf("failed encoding report: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package failure
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"video-ai-stt/config"
+	"video-ai-stt/internal/job"
+	"video-ai-stt/internal/process"
+)
+
+func newTestRecorder(t *testing.T) (*Recorder, *process.ProcessedManager) {
+	t.Helper()
+
+	store, err := process.NewStore(config.Store{Type: process.STORE_TYPE_MEMORY})
+	if err != nil {
+		t.Fatal(err)
+	}
+	manager, err := process.NewProcessedManager(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewRecorder(config.Failure{Dir: filepath.Join(t.TempDir(), "failed")}, manager), manager
+}
+
+func timeoutContext(t *testing.T) context.Context {
+	ctx, cancel := context.WithTimeout(context.Background(), 0)
+	t.Cleanup(cancel)
+	<-ctx.Done()
+	return ctx
+}
+
+func TestFail(t *testing.T) {
+	tests := []struct {
+		name       string
+		cause      error
+		wantReason string
+		wantMoved  bool
+	}{
+		{name: "move failed video", cause: errors.New("ffmpeg exit 1"), wantMoved: true},
+		{name: "timeout", cause: Timeout(timeoutContext(t), 0, errors.New("killed")), wantReason: REASON_TIMEOUT, wantMoved: true},
+		{name: "not media stays in place", cause: NotMedia(errors.New("invalid data")), wantReason: REASON_NOT_MEDIA, wantMoved: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder, manager := newTestRecorder(t)
+			videoPath := filepath.Join(t.TempDir(), "a.mp4")
+			if err := os.WriteFile(videoPath, []byte("data"), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			jobs := job.NewJob(videoPath, "a.mp4")
+			manager.MarkProcessed(jobs, process.EXTRACT_AUDIO_START)
+			recorder.Fail(jobs, process.PROBE_MEDIA_FAILED, tt.cause)
+
+			record, _ := manager.Load(videoPath)
+			if record.Step != process.PROBE_MEDIA_FAILED || record.Reason != tt.wantReason {
+				t.Errorf("record step = %d, reason = %q", record.Step, record.Reason)
+			}
+
+			_, err := os.Stat(videoPath)
+			if moved := os.IsNotExist(err); moved != tt.wantMoved {
+				t.Errorf("moved = %v, want %v", moved, tt.wantMoved)
+			}
+			if tt.wantMoved != (record.FailedPath != "") {
+				t.Errorf("failed path = %q", record.FailedPath)
+			}
+			if !tt.wantMoved && manager.IsRetryable(videoPath) {
+				t.Error("failed job in place must not be retried by the watcher")
+			}
+
+			reportPath := filepath.Join(recorder.cfg.Dir, "a.mp4.error.json")
+			if _, err := os.Stat(reportPath); err != nil {
+				t.Errorf("report not written: %v", err)
+			}
+		})
+	}
+}
@@ -1,11 +1,14 @@
 package groq
 
+import "video-ai-stt/internal/stt"
+
 type STTResp struct {
 	Task     string     `json:"task"`
 	Language string     `json:"language"`
 	Duration float64    `json:"duration"`
 	Text     string     `json:"text"`
 	Segments []Segments `json:"segments"`
+	Words    []WordSPEC `json:"words"`
 	XGroq    XGroq      `json:"x_groq"`
 }
 
@@ -54,3 +57,33 @@ type WordSPEC struct {
 	Start float64 `json:"start"`
 	End   float64 `json:"end"`
 }
+
+func (r *STTResp) toTranscript(model string) *stt.Transcript {
+	transcript := &stt.Transcript{
+		Provider: stt.PROVIDER_GROQ,
+		Model:    model,
+		Language: r.Language,
+		Duration: r.Duration,
+		Text:     r.Text,
+		Segments: make([]stt.Segment, 0, len(r.Segments)),
+	}
+
+	for _, segment := range r.Segments {
+		transcript.Segments = append(transcript.Segments, stt.Segment{
+			ID:         segment.ID,
+			Start:      segment.Start,
+			End:        segment.End,
+			Text:       segment.Text,
+			AvgLogProb: segment.AvgLogProb,
+		})
+	}
+
+	for _, word := range r.Words {
+		transcript.Words = append(transcript.Words, stt.Word{
+			Word:  word.Word,
+			Start: word.Start,
+			End:   word.End,
+		})
+	}
+	return transcript
+}
@@ -0,0 +1,152 @@
+package hangul
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// particles 앞 어절에 붙여 써야 하는 조사
+// 단독 어절로도 쓰이는 이, 가, 도, 만, 의 등과 용언과 형태가 같은 하고(하다), 보다(보다), 와(오다)는 오인 가능성이 있어 제외
+var particles = map[string]bool{
+	"은": true, "는": true, "을": true, "를": true,
+	"에": true, "에서": true, "에게": true, "께서": true, "한테": true,
+	"으로": true, "로": true, "으로는": true, "에는": true, "에서는": true, "에도": true,
+	"과": true, "랑": true, "이랑": true,
+	"까지": true, "부터": true, "처럼": true, "마다": true, "조차": true,
+}
+
+// latinParticles 단독 어절로도 쓰이지만 영문/숫자 바로 뒤에서는 조사로 볼 수 있는 것 (ex: "OpenAI 의", "GPT 가")
+var latinParticles = map[string]bool{
+	"이": true, "가": true, "의": true, "도": true, "만": true, "나": true, "이나": true,
+}
+
+// closingPunct 앞 글자에 붙여 쓰는 문장 부호
+const closingPunct = ".,?!;:)]}…」』”’%"
+
+// openingPunct 뒤 글자에 붙여 쓰는 문장 부호
+const openingPunct = "([{「『“‘"
+
+var missingSpaceRegex = regexp.MustCompile(`([가-힣])([.?!,])([가-힣])`)
+
+// IsParticle 띄어 쓰여진 조사 어절인지
+func IsParticle(token string) bool {
+	return particles[strings.TrimRight(token, closingPunct)]
+}
+
+// IsAttachable 앞 어절에 붙여야 하는 토큰 (띄어 쓰여진 조사 또는 닫는 문장 부호)
+func IsAttachable(prev, token string) bool {
+	if prev == "" || token == "" {
+		return false
+	}
+
+	if strings.Trim(token, closingPunct) == "" {
+		return true
+	}
+
+	// 앞 어절이 문장 부호로 끝나면 조사가 아닌 새 어절로 봄
+	last, _ := utf8.DecodeLastRuneInString(prev)
+	if latinParticles[strings.TrimRight(token, closingPunct)] {
+		return !IsHangul(last) && (unicode.IsLetter(last) || unicode.IsDigit(last))
+	}
+
+	return IsParticle(token) && (unicode.IsLetter(last) || unicode.IsDigit(last))
+}
+
+// Normalize 공백을 정리하고 문장 부호 주변 띄어쓰기와 띄어 쓰여진 조사를 바로잡음
+func Normalize(text string) string {
+	// 한글 문장 사이에 붙어버린 문장 부호 뒤 띄어쓰기 (3.14, URL 등 숫자/영문은 유지)
+	text = missingSpaceRegex.ReplaceAllString(text, "$1$2 $3")
+
+	var eojeols []string
+	for _, token := range strings.Fields(text) {
+		if n := len(eojeols); n > 0 {
+			// ".다음은" 처럼 앞 문장의 부호가 다음 어절에 붙은 경우
+			if rest := strings.TrimLeft(token, closingPunct); rest != "" && rest != token {
+				eojeols[n-1] += token[:len(token)-len(rest)]
+				token = rest
+			}
+
+			prev := eojeols[n-1]
+			if IsAttachable(prev, token) || endsWithAny(prev, openingPunct) {
+				eojeols[n-1] = prev + token
+				continue
+			}
+		}
+		eojeols = append(eojeols, token)
+	}
+	return strings.Join(eojeols, " ")
+}
+
+func endsWithAny(text, chars string) bool {
+	last, _ := utf8.DecodeLastRuneInString(text)
+	return strings.ContainsRune(chars, last)
+}
+
+// Eojeols 정규화된 텍스트를 어절 단위로 분리, 조사는 앞 어절에 붙은 상태로 유지
+func Eojeols(text string) []string {
+	return strings.Fields(Normalize(text))
+}
+
+// SplitRuns 한 줄에 들어가지 않는 긴 어절을 한글/비한글 경계에서 나눔
+// 끝에 붙은 조사와 문장 부호는 앞 조각에 남김
+func SplitRuns(eojeol string, maxWidth int) []string {
+	if Width(eojeol) <= maxWidth {
+		return []string{eojeol}
+	}
+
+	var runs []string
+	var current strings.Builder
+	prevHangul := false
+	for i, r := range eojeol {
+		hangul := IsHangul(r)
+		if i > 0 && hangul != prevHangul && unicode.IsLetter(r) {
+			runs = append(runs, current.String())
+			current.Reset()
+		}
+		current.WriteRune(r)
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			prevHangul = hangul
+		}
+	}
+	runs = append(runs, current.String())
+
+	// 조사로 끝나는 조각은 앞 조각과 합침
+	var merged []string
+	for _, run := range runs {
+		if n := len(merged); n > 0 && (IsParticle(run) || strings.Trim(run, closingPunct) == "") {
+			merged[n-1] += run
+			continue
+		}
+		merged = append(merged, run)
+	}
+
+	// 여전히 긴 조각은 표시 폭 기준으로 자름
+	var pieces []string
+	for _, run := range merged {
+		pieces = append(pieces, splitWidth(run, maxWidth)...)
+	}
+	return pieces
+}
+
+func splitWidth(text string, maxWidth int) []string {
+	if maxWidth <= 0 || Width(text) <= maxWidth {
+		return []string{text}
+	}
+
+	var pieces []string
+	var current strings.Builder
+	width := 0
+	for _, r := range text {
+		w := RuneWidth(r)
+		if width+w > maxWidth && current.Len() > 0 {
+			pieces = append(pieces, current.String())
+			current.Reset()
+			width = 0
+		}
+		current.WriteRune(r)
+		width += w
+	}
+	return append(pieces, current.String())
+}
@@ -0,0 +1,78 @@
+package hangul
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "바르게 띄어 쓴 문장", text: "밥을 먹고 공부를 하고 잤다.", want: "밥을 먹고 공부를 하고 잤다."},
+		{name: "용언 보다", text: "하늘을 보다.", want: "하늘을 보다."},
+		{name: "용언 와", text: "이리 와.", want: "이리 와."},
+		{name: "띄어 쓴 조사", text: "회의 는 내일 오후 까지 입니다", want: "회의는 내일 오후까지 입니다"},
+		{name: "조사와 문장 부호", text: "서울 에서 출발 합니다 .", want: "서울에서 출발 합니다."},
+		{name: "영문 뒤 조사", text: "OpenAI 의 GPT 가 발표됐다", want: "OpenAI의 GPT가 발표됐다"},
+		{name: "한글 뒤 단독 어절", text: "이 가방 도 좋다", want: "이 가방 도 좋다"},
+		{name: "붙어버린 문장 부호", text: "감사합니다.다음은 질문입니다", want: "감사합니다. 다음은 질문입니다"},
+		{name: "앞 어절로 옮기는 문장 부호", text: "끝 .다음", want: "끝. 다음"},
+		{name: "여는 괄호", text: "( 참고 ) 자료", want: "(참고) 자료"},
+		{name: "숫자와 URL 유지", text: "버전 3.14 는 example.com 에", want: "버전 3.14는 example.com에"},
+		{name: "연속 공백", text: "  안녕   하세요  ", want: "안녕 하세요"},
+		{name: "빈 문자열", text: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Normalize(tt.text); got != tt.want {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsAttachable(t *testing.T) {
+	tests := []struct {
+		prev  string
+		token string
+		want  bool
+	}{
+		{prev: "공부를", token: "하고", want: false},
+		{prev: "하늘을", token: "보다", want: false},
+		{prev: "학교", token: "에서", want: true},
+		{prev: "학교", token: "에서,", want: true},
+		{prev: "GPT", token: "가", want: true},
+		{prev: "우리", token: "가", want: false},
+		{prev: "끝", token: "...", want: true},
+		{prev: "", token: "는", want: false},
+		{prev: "그래서,", token: "는", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := IsAttachable(tt.prev, tt.token); got != tt.want {
+			t.Errorf("IsAttachable(%q, %q) = %v, want %v", tt.prev, tt.token, got, tt.want)
+		}
+	}
+}
+
+func TestSplitRuns(t *testing.T) {
+	tests := []struct {
+		eojeol   string
+		maxWidth int
+		want     []string
+	}{
+		{eojeol: "짧은", maxWidth: 10, want: []string{"짧은"}},
+		{eojeol: "Kubernetes클러스터에서", maxWidth: 12, want: []string{"Kubernetes", "클러스터에서"}},
+		{eojeol: "가나다라마바사", maxWidth: 6, want: []string{"가나다", "라마바", "사"}},
+	}
+
+	for _, tt := range tests {
+		if got := SplitRuns(tt.eojeol, tt.maxWidth); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SplitRuns(%q, %d) = %q, want %q", tt.eojeol, tt.maxWidth, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package hangul
+
+import "unicode"
+
+// Width 고정폭 자막 렌더링 기준 표시 폭, 한글·한자·전각 문자는 2칸, 결합 문자는 0칸
+func Width(text string) int {
+	width := 0
+	for _, r := range text {
+		width += RuneWidth(r)
+	}
+	return width
+}
+
+func RuneWidth(r rune) int {
+	switch {
+	case r == 0 || unicode.Is(unicode.Mn, r) || unicode.Is(unicode.Me, r) || unicode.Is(unicode.Cf, r):
+		return 0
+	case isWide(r):
+		return 2
+	default:
+		return 1
+	}
+}
+
+func isWide(r rune) bool {
+	switch {
+	case r >= 0x1100 && r <= 0x115F: // 한글 자모 (초성)
+		return true
+	case r >= 0x2E80 && r <= 0x303E: // CJK 부수, 기호 및 구두점
+		return true
+	case r >= 0x3041 && r <= 0x33FF: // 히라가나, 가타카나, 한글 호환 자모, CJK 호환
+		return true
+	case r >= 0x3400 && r <= 0x4DBF: // CJK 확장 A
+		return true
+	case r >= 0x4E00 && r <= 0x9FFF: // CJK 통합 한자
+		return true
+	case r >= 0xA960 && r <= 0xA97F: // 한글 자모 확장 A
+		return true
+	case r >= 0xAC00 && r <= 0xD7A3: // 한글 음절
+		return true
+	case r >= 0xF900 && r <= 0xFAFF: // CJK 호환 한자
+		return true
+	case r >= 0xFE30 && r <= 0xFE4F: // CJK 호환 형태
+		return true
+	case r >= 0xFF00 && r <= 0xFF60: // 전각 문자
+		return true
+	case r >= 0xFFE0 && r <= 0xFFE6:
+		return true
+	case r >= 0x1F300 && r <= 0x1FAFF: // 이모지
+		return true
+	}
+	return false
+}
+
+func IsHangul(r rune) bool {
+	return (r >= 0xAC00 && r <= 0xD7A3) || (r >= 0x1100 && r <= 0x11FF) || (r >= 0x3130 && r <= 0x318F)
+}
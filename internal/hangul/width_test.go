@@ -0,0 +1,26 @@
+package hangul
+
+import "testing"
+
+func TestWidth(t *testing.T) {
+	tests := []struct {
+		text string
+		want int
+	}{
+		{text: "", want: 0},
+		{text: "abc", want: 3},
+		{text: "한글", want: 4},
+		{text: "GPT가", want: 5},
+		{text: "漢字", want: 4},
+		{text: "ｱＡ", want: 3},
+		{text: "é", want: 1},
+		{text: "​", want: 0},
+		{text: "🎬", want: 2},
+	}
+
+	for _, tt := range tests {
+		if got := Width(tt.text); got != tt.want {
+			t.Errorf("Width(%q) = %d, want %d", tt.text, got, tt.want)
+		}
+	}
+}
@@ -1,13 +1,40 @@
 package job
 
-import "github.com/google/uuid"
+import (
+	"github.com/google/uuid"
+	"path/filepath"
+	"strings"
+	"video-ai-stt/config"
+	"video-ai-stt/internal/media"
+)
 
 type Job struct {
-	rid       string
-	videoPath string
-	audioPath string
-	filename  string
-	step      int
+	rid            string
+	videoPath      string
+	audioPath      string
+	filename       string
+	step           int
+	errMsg         string
+	reason         string
+	failedPath     string
+	silences       []media.Silence
+	transcriptPath string
+	artifacts      map[string]string
+	metadata       Metadata
+	media          *media.Info
+	profile        config.Profile
+}
+
+// Metadata 업로드 시 함께 전달된 작업 정보
+type Metadata struct {
+	Title     string `json:"title,omitempty"`
+	Language  string `json:"language,omitempty"`
+	Requester string `json:"requester,omitempty"`
+	// Prompt, Vocabulary 작업별 전사 힌트, 프로필과 전역 설정보다 우선
+	Prompt     string   `json:"prompt,omitempty"`
+	Vocabulary []string `json:"vocabulary,omitempty"`
+	// Profile 업로드 파일을 저장할 watch 디렉토리의 프로필, 비어 있으면 default
+	Profile string `json:"profile,omitempty"`
 }
 
 func NewJob(videoPath, filename string) *Job {
@@ -18,6 +45,18 @@ func NewJob(videoPath, filename string) *Job {
 	}
 }
 
+// RestoreJob 저장소에 기록된 작업을 재구성 (crash recovery)
+func RestoreJob(rid, videoPath, audioPath, transcriptPath, filename string, step int) *Job {
+	return &Job{
+		rid:            rid,
+		videoPath:      videoPath,
+		audioPath:      audioPath,
+		transcriptPath: transcriptPath,
+		filename:       filename,
+		step:           step,
+	}
+}
+
 func (j *Job) GetRID() string {
 	return j.rid
 }
@@ -41,3 +80,125 @@ func (j *Job) SetAudioPath(path string) {
 func (j *Job) GetAudioPath() string {
 	return j.audioPath
 }
+
+func (j *Job) GetFilename() string {
+	return j.filename
+}
+
+func (j *Job) GetStep() int {
+	return j.step
+}
+
+func (j *Job) SetError(errMsg string) {
+	j.errMsg = errMsg
+}
+
+func (j *Job) GetError() string {
+	return j.errMsg
+}
+
+// SetReason 실패 원인 분류 (timeout ...)
+func (j *Job) SetReason(reason string) {
+	j.reason = reason
+}
+
+func (j *Job) GetReason() string {
+	return j.reason
+}
+
+func (j *Job) SetFailedPath(path string) {
+	j.failedPath = path
+}
+
+func (j *Job) GetFailedPath() string {
+	return j.failedPath
+}
+
+func (j *Job) SetSilences(silences []media.Silence) {
+	j.silences = silences
+}
+
+func (j *Job) GetSilences() []media.Silence {
+	return j.silences
+}
+
+func (j *Job) SetTranscriptPath(path string) {
+	j.transcriptPath = path
+}
+
+func (j *Job) GetTranscriptPath() string {
+	return j.transcriptPath
+}
+
+// AddArtifact 생성된 결과물 경로를 출력 형식별로 기록
+func (j *Job) AddArtifact(format, path string) {
+	if j.artifacts == nil {
+		j.artifacts = make(map[string]string)
+	}
+	j.artifacts[format] = path
+}
+
+func (j *Job) GetArtifacts() map[string]string {
+	return j.artifacts
+}
+
+func (j *Job) SetMetadata(metadata Metadata) {
+	j.metadata = metadata
+}
+
+func (j *Job) GetMetadata() Metadata {
+	return j.metadata
+}
+
+func (j *Job) SetMedia(info *media.Info) {
+	j.media = info
+}
+
+func (j *Job) GetMedia() *media.Info {
+	return j.media
+}
+
+// SetProfile 작업이 등록된 watch 디렉토리의 프로필
+func (j *Job) SetProfile(profile config.Profile) {
+	j.profile = profile
+}
+
+func (j *Job) GetProfile() config.Profile {
+	return j.profile
+}
+
+// GetLanguage 업로드 시 전달된 언어 힌트, 없으면 프로필의 언어 힌트
+func (j *Job) GetLanguage() string {
+	if j.metadata.Language != "" {
+		return j.metadata.Language
+	}
+	return j.profile.Language
+}
+
+// OutputName 중간 결과물(오디오, transcript) 파일 이름 (확장자 제외)
+// 다른 watch 디렉토리에 같은 이름의 영상이 있어도 겹치지 않도록 rid 를 붙임
+func (j *Job) OutputName() string {
+	return j.rid + "_" + strings.TrimSuffix(j.filename, filepath.Ext(j.filename))
+}
+
+// RelativeDir 프로필 watch 디렉토리 기준 영상이 있는 하위 폴더, watch 디렉토리 바로 아래거나 밖에 있으면 ""
+func (j *Job) RelativeDir() string {
+	if j.profile.WatchDir == "" {
+		return ""
+	}
+
+	root, err := filepath.Abs(j.profile.WatchDir)
+	if err != nil {
+		return ""
+	}
+	dir, err := filepath.Abs(filepath.Dir(j.videoPath))
+	if err != nil {
+		return ""
+	}
+
+	rel, err := filepath.Rel(root, dir)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return ""
+	}
+	return rel
+}
@@ -0,0 +1,108 @@
+package media
+
+import "strings"
+
+const (
+	CODEC_TYPE_VIDEO = "video"
+	CODEC_TYPE_AUDIO = "audio"
+)
+
+// Info ffprobe 로 확인한 컨테이너와 스트림 정보
+type Info struct {
+	FormatName   string   `json:"format_name"`
+	Duration     float64  `json:"duration"`
+	Size         int64    `json:"size,omitempty"`
+	BitRate      int64    `json:"bit_rate,omitempty"`
+	VideoStreams []Stream `json:"video_streams,omitempty"`
+	AudioStreams []Stream `json:"audio_streams,omitempty"`
+}
+
+// Stream 스트림 하나의 코덱 정보, Index 는 입력 파일 기준 스트림 번호 (ffmpeg -map 0:<index>)
+type Stream struct {
+	Index      int     `json:"index"`
+	CodecType  string  `json:"codec_type"`
+	CodecName  string  `json:"codec_name"`
+	Language   string  `json:"language,omitempty"`
+	BitRate    int64   `json:"bit_rate,omitempty"`
+	SampleRate int     `json:"sample_rate,omitempty"`
+	Channels   int     `json:"channels,omitempty"`
+	Duration   float64 `json:"duration,omitempty"`
+	Default    bool    `json:"default,omitempty"`
+}
+
+func (i *Info) HasAudio() bool {
+	return len(i.AudioStreams) > 0
+}
+
+// IsAudioOnly 영상 스트림 없이 오디오만 있는 입력 (앨범 커버 같은 정지 이미지는 영상으로 보지 않음)
+func (i *Info) IsAudioOnly() bool {
+	return i.HasAudio() && len(i.VideoStreams) == 0
+}
+
+// Languages 오디오 트랙 언어 목록 (중복 제외)
+func (i *Info) Languages() []string {
+	var languages []string
+	seen := map[string]bool{}
+	for _, stream := range i.AudioStreams {
+		if stream.Language == "" || seen[stream.Language] {
+			continue
+		}
+		seen[stream.Language] = true
+		languages = append(languages, stream.Language)
+	}
+	return languages
+}
+
+// PrimaryAudio 전사할 오디오 트랙 선택, 언어 힌트와 같은 트랙 > default 트랙 > 첫 번째 트랙 순
+func (i *Info) PrimaryAudio(language string) (Stream, bool) {
+	if !i.HasAudio() {
+		return Stream{}, false
+	}
+
+	if language != "" {
+		for _, stream := range i.AudioStreams {
+			if matchLanguage(stream.Language, language) {
+				return stream, true
+			}
+		}
+	}
+
+	for _, stream := range i.AudioStreams {
+		if stream.Default {
+			return stream, true
+		}
+	}
+	return i.AudioStreams[0], true
+}
+
+// matchLanguage 컨테이너의 ISO 639-2 언어 태그(kor)와 ISO 639-1 언어 힌트(ko) 비교
+func matchLanguage(tag, hint string) bool {
+	tag = strings.ToLower(tag)
+	hint = strings.ToLower(hint)
+	if tag == "" || hint == "" {
+		return false
+	}
+	if tag == hint {
+		return true
+	}
+	return iso639_2[tag] == hint
+}
+
+var iso639_2 = map[string]string{
+	"kor": "ko",
+	"eng": "en",
+	"jpn": "ja",
+	"chi": "zh",
+	"zho": "zh",
+	"spa": "es",
+	"fra": "fr",
+	"fre": "fr",
+	"deu": "de",
+	"ger": "de",
+	"rus": "ru",
+	"por": "pt",
+	"ita": "it",
+	"vie": "vi",
+	"tha": "th",
+	"ind": "id",
+}
@@ -0,0 +1,16 @@
+package media
+
+// Silence ffmpeg silencedetect 로 검출한 무음 구간(초)
+type Silence struct {
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+}
+
+func (s Silence) Duration() float64 {
+	return s.End - s.Start
+}
+
+// Middle 무음 구간의 중간 지점, 오디오를 자를 때 가장 안전한 위치
+func (s Silence) Middle() float64 {
+	return (s.Start + s.End) / 2
+}
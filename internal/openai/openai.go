@@ -0,0 +1,123 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"video-ai-stt/config"
+	"video-ai-stt/internal/stt"
+)
+
+const RESPONSE_FORMAT_VERBOSE_JSON = "verbose_json"
+
+// OpenAI openai 호환 /v1/audio/transcriptions api 를 사용하는 Transcriber
+type OpenAI struct {
+	cfg   config.OpenAI
+	retry stt.RetryPolicy
+}
+
+func NewOpenAI(cfg config.OpenAI, retry stt.RetryPolicy) *OpenAI {
+	return &OpenAI{
+		cfg:   cfg,
+		retry: retry,
+	}
+}
+
+func (o *OpenAI) Name() string {
+	return stt.PROVIDER_OPENAI
+}
+
+func (o *OpenAI) Transcribe(ctx context.Context, audioPath string, opts stt.Options) (*stt.Transcript, error) {
+
+	model := o.cfg.STTUseModel
+	if opts.Model != "" {
+		model = opts.Model
+	}
+
+	logger := slog.With("rid", opts.RID, "audio_path", audioPath, "provider", o.Name(), "model", model)
+
+	fields := []stt.Field{
+		{Name: "model", Value: model},
+		{Name: "temperature", Value: "0"},
+		{Name: "response_format", Value: o.cfg.ResponseFormat},
+	}
+	// gpt-4o 계열은 json 만 지원하며 timestamp_granularities 는 verbose_json 에서만 허용
+	if o.cfg.ResponseFormat == RESPONSE_FORMAT_VERBOSE_JSON {
+		fields = append(fields,
+			stt.Field{Name: "timestamp_granularities[]", Value: "word"},
+			stt.Field{Name: "timestamp_granularities[]", Value: "segment"},
+		)
+	}
+	fields = append(fields, opts.Fields()...)
+
+	body, err := stt.PostMultipart(ctx, logger, o.retry, stt.Request{
+		Endpoint: o.cfg.STTEndpoint,
+		APIToken: o.cfg.APIToken,
+		FilePath: audioPath,
+		Progress: opts.Progress,
+		Fields:   fields,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := transcriptionResp{}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("%w: failed unmarshalling response: %w, body : %s", stt.ErrInvalidResponse, err, string(body))
+	}
+
+	logger.Info("openai audio transcriptions result", "duration", resp.Duration, "language", resp.Language)
+	return resp.toTranscript(model), nil
+}
+
+type transcriptionResp struct {
+	Task     string    `json:"task"`
+	Language string    `json:"language"`
+	Duration float64   `json:"duration"`
+	Text     string    `json:"text"`
+	Segments []segment `json:"segments"`
+	Words    []word    `json:"words"`
+}
+
+type segment struct {
+	ID           int64   `json:"id"`
+	Start        float64 `json:"start"`
+	End          float64 `json:"end"`
+	Text         string  `json:"text"`
+	AvgLogProb   float64 `json:"avg_logprob"`
+	NoSpeechProb float64 `json:"no_speech_prob"`
+}
+
+type word struct {
+	Word  string  `json:"word"`
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+}
+
+func (r *transcriptionResp) toTranscript(model string) *stt.Transcript {
+	transcript := &stt.Transcript{
+		Provider: stt.PROVIDER_OPENAI,
+		Model:    model,
+		Language: r.Language,
+		Duration: r.Duration,
+		Text:     r.Text,
+		Segments: make([]stt.Segment, 0, len(r.Segments)),
+	}
+
+	for _, s := range r.Segments {
+		transcript.Segments = append(transcript.Segments, stt.Segment{
+			ID:           s.ID,
+			Start:        s.Start,
+			End:          s.End,
+			Text:         s.Text,
+			AvgLogProb:   s.AvgLogProb,
+			NoSpeechProb: s.NoSpeechProb,
+		})
+	}
+
+	for _, w := range r.Words {
+		transcript.Words = append(transcript.Words, stt.Word{Word: w.Word, Start: w.Start, End: w.End})
+	}
+	return transcript
+}
@@ -0,0 +1,60 @@
+package pipeline
+
+import (
+	"context"
+	"sync"
+)
+
+// Pool 파이프라인 단계의 동시 실행 수 제한
+// 빈 자리가 생길 때까지 다음 작업을 받지 않으므로 이전 단계의 queue 가 차오르며 backpressure 가 전달됨
+type Pool struct {
+	sem chan struct{}
+	wg  sync.WaitGroup
+}
+
+func NewPool(size int) *Pool {
+	if size < 1 {
+		size = 1
+	}
+	return &Pool{
+		sem: make(chan struct{}, size),
+	}
+}
+
+// Acquire 실행 자리를 확보, ctx 가 종료되면 false
+func (p *Pool) Acquire(ctx context.Context) bool {
+	select {
+	case p.sem <- struct{}{}:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// Release Acquire 로 확보한 자리를 실행 없이 반환
+func (p *Pool) Release() {
+	<-p.sem
+}
+
+// Go Acquire 로 확보한 자리에서 fn 을 실행하고 끝나면 자리를 반환
+func (p *Pool) Go(fn func()) {
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		defer p.Release()
+		fn()
+	}()
+}
+
+func (p *Pool) Wait() {
+	p.wg.Wait()
+}
+
+// Running 실행 중인 작업 수
+func (p *Pool) Running() int {
+	return len(p.sem)
+}
+
+func (p *Pool) Size() int {
+	return cap(p.sem)
+}
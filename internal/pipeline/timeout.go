@@ -0,0 +1,14 @@
+package pipeline
+
+import (
+	"context"
+	"time"
+)
+
+// WithTimeout 단계별 제한 시간, 0 이하이면 제한 없음
+func WithTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
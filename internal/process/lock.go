@@ -0,0 +1,47 @@
+package process
+
+import "sync"
+
+// keyLocks key 별 mutex, 잠금을 기다리거나 잡고 있는 요청이 없으면 항목을 제거하여 작업 수만큼 늘어나지 않음
+type keyLocks struct {
+	mu   sync.Mutex
+	held map[string]*keyLock
+}
+
+type keyLock struct {
+	sync.Mutex
+	refs int
+}
+
+func newKeyLocks() *keyLocks {
+	return &keyLocks{held: make(map[string]*keyLock)}
+}
+
+func (l *keyLocks) lock(key string) func() {
+	l.mu.Lock()
+	kl, ok := l.held[key]
+	if !ok {
+		kl = &keyLock{}
+		l.held[key] = kl
+	}
+	kl.refs++
+	l.mu.Unlock()
+
+	kl.Lock()
+	return func() {
+		kl.Unlock()
+
+		l.mu.Lock()
+		kl.refs--
+		if kl.refs == 0 {
+			delete(l.held, key)
+		}
+		l.mu.Unlock()
+	}
+}
+
+func (l *keyLocks) count() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.held)
+}
@@ -0,0 +1,53 @@
+package process
+
+import (
+	"sync"
+	"testing"
+	"video-ai-stt/internal/job"
+)
+
+func TestKeyLocksSerializeAndRelease(t *testing.T) {
+	locks := newKeyLocks()
+
+	counter := 0
+	wg := sync.WaitGroup{}
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			unlock := locks.lock("a.mp4")
+			defer unlock()
+			current := counter
+			counter = current + 1
+		}()
+	}
+	wg.Wait()
+
+	if counter != 50 {
+		t.Errorf("counter = %d, want 50", counter)
+	}
+	if n := locks.count(); n != 0 {
+		t.Errorf("lock entries = %d, want 0", n)
+	}
+}
+
+// 작업이 끝나거나 잊혀진 뒤에는 lock 항목이 남지 않음
+func TestManagerLocksReleased(t *testing.T) {
+	manager := newTestManager(t)
+
+	for _, name := range []string{"a.mp4", "b.mp4", "c.mp4"} {
+		jobs := job.NewJob("/uploads/"+name, name)
+		manager.MarkProcessed(jobs, WATCHER_FILE_REGISTER)
+		manager.MarkProcessed(jobs, ALL_PROCESS_COMPLETE)
+	}
+	cancelled := job.NewJob("/uploads/d.mp4", "d.mp4")
+	manager.MarkProcessed(cancelled, WATCHER_FILE_REGISTER)
+	if _, err := manager.Cancel(cancelled.GetRID()); err != nil {
+		t.Fatal(err)
+	}
+	manager.Forget("/uploads/a.mp4")
+
+	if n := manager.locks.count(); n != 0 {
+		t.Errorf("lock entries = %d, want 0", n)
+	}
+}
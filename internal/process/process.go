@@ -1,6 +1,14 @@
 package process
 
-import "sync"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+	"video-ai-stt/internal/job"
+)
 
 const (
 	WATCHER_FILE_REGISTER      = iota + 1 // 1: 작업대상파일로 등록
@@ -13,28 +21,264 @@ const (
 	ALL_PROCESS_COMPLETE                  // 8: 모든 process 완료
 )
 
+const (
+	EXTRACT_AUDIO_FAILED     = iota + 101 // 101: 영상에서 오디오 추출 실패
+	REQUEST_GROQ_API_FAILED               // 102: groq api request 실패
+	GENERATE_SUBTITLE_FAILED              // 103: 자막 파일 생성 실패
+	PROBE_MEDIA_FAILED                    // 104: 미디어 확인 실패 (미디어가 아니거나 오디오 스트림 없음)
+)
+
+// JOB_CANCELLED api 요청으로 취소된 작업, 이후 단계는 모두 건너뜀
+const JOB_CANCELLED = 100
+
+var (
+	ErrJobNotFound = errors.New("job not found")
+	ErrJobFinished = errors.New("job already finished")
+)
+
+func IsFailed(step int) bool {
+	return step >= EXTRACT_AUDIO_FAILED
+}
+
+// IsFinished 더 이상 진행되지 않는 작업 (완료, 실패, 취소)
+func IsFinished(step int) bool {
+	return step >= ALL_PROCESS_COMPLETE
+}
+
 type ProcessedManager struct {
 	memory *sync.Map
+	// rids rid 로 작업을 조회하기 위한 색인 (rid → 영상 경로)
+	rids *sync.Map
+	// cancels 진행 중인 작업의 context 취소 함수 (rid → context.CancelFunc)
+	cancels *sync.Map
+	// progress 전사 중인 작업의 업로드 진행 상황 (rid → UploadProgress)
+	progress *sync.Map
+	// locks 같은 작업 기록의 읽기-수정-쓰기를 직렬화 (영상 경로 단위)
+	locks *keyLocks
+	store Store
 }
 
-func NewProcessedManager() *ProcessedManager {
-	return &ProcessedManager{memory: &sync.Map{}}
+// NewProcessedManager store 에 저장된 작업 상태를 읽어 메모리에 적재
+func NewProcessedManager(store Store) (*ProcessedManager, error) {
+	records, err := store.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed loading job records: %w", err)
+	}
+
+	memory := &sync.Map{}
+	rids := &sync.Map{}
+	for _, record := range records {
+		memory.Store(record.VideoPath, record)
+		rids.Store(record.RID, record.VideoPath)
+	}
+
+	slog.Debug("processed manager loaded", "record_count", len(records))
+	return &ProcessedManager{memory: memory, rids: rids, cancels: &sync.Map{}, progress: &sync.Map{}, locks: newKeyLocks(), store: store}, nil
 }
 
 func (p *ProcessedManager) IsProcessed(key string, expected int) bool {
-	val, ok := p.memory.Load(key)
+	record, ok := p.Load(key)
+	if !ok {
+		return false
+	}
+
+	return record.Step >= expected
+}
+
+// IsRetryable 실패 처리되어 failed 디렉토리로 옮겨진 파일이 원래 경로에 다시 올라온 경우
+func (p *ProcessedManager) IsRetryable(key string) bool {
+	record, ok := p.Load(key)
 	if !ok {
 		return false
 	}
 
-	v, ok := val.(int)
+	return IsFailed(record.Step) && record.FailedPath != "" && record.FailedPath != key
+}
+
+func (p *ProcessedManager) IsCancelled(key string) bool {
+	record, ok := p.Load(key)
+	return ok && record.Step == JOB_CANCELLED
+}
+
+func (p *ProcessedManager) Load(key string) (Record, bool) {
+	val, ok := p.memory.Load(key)
+	if !ok {
+		return Record{}, false
+	}
+
+	record, ok := val.(Record)
+	return record, ok
+}
+
+func (p *ProcessedManager) LoadByRID(rid string) (Record, bool) {
+	val, ok := p.rids.Load(rid)
 	if !ok {
+		return Record{}, false
+	}
+
+	record, ok := p.Load(val.(string))
+	if !ok || record.RID != rid {
+		return Record{}, false
+	}
+	return record, true
+}
+
+func (p *ProcessedManager) Records() []Record {
+	var records []Record
+	p.memory.Range(func(_, value any) bool {
+		if record, ok := value.(Record); ok {
+			records = append(records, record)
+		}
+		return true
+	})
+	return records
+}
+
+// MarkProcessed 작업 상태를 value 단계로 기록, 끝난 작업(완료, 실패, 취소)은 다른 단계로 옮기지 않고 false
+func (p *ProcessedManager) MarkProcessed(jobs *job.Job, value int) bool {
+	unlock := p.lock(jobs.GetVideoPath())
+	defer unlock()
+
+	now := time.Now()
+	record := Record{
+		RID:            jobs.GetRID(),
+		VideoPath:      jobs.GetVideoPath(),
+		AudioPath:      jobs.GetAudioPath(),
+		TranscriptPath: jobs.GetTranscriptPath(),
+		Filename:       jobs.GetFilename(),
+		Step:           value,
+		Error:          jobs.GetError(),
+		Reason:         jobs.GetReason(),
+		FailedPath:     jobs.GetFailedPath(),
+		Artifacts:      jobs.GetArtifacts(),
+		Metadata:       jobs.GetMetadata(),
+		Media:          jobs.GetMedia(),
+		Silences:       jobs.GetSilences(),
+		Profile:        jobs.GetProfile(),
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if prev, ok := p.Load(record.VideoPath); ok && prev.RID == record.RID {
+		// 취소되거나 끝난 작업은 진행 중이던 단계가 끝나더라도 상태를 덮어쓰지 않음
+		if IsFinished(prev.Step) && prev.Step != value {
+			slog.Debug("skip mark processed, job finished", "rid", record.RID, "video_path", record.VideoPath, "recorded_step", prev.Step, "step", value)
+			return false
+		}
+		record.CreatedAt = prev.CreatedAt
+	}
+
+	jobs.MarkProcessed(value)
+	p.save(record)
+	return true
+}
+
+// JobContext 작업 단위 context, Cancel 로 작업을 취소하면 진행 중인 ffmpeg 과 STT 요청도 중단됨
+func (p *ProcessedManager) JobContext(ctx context.Context, rid string) (context.Context, context.CancelFunc) {
+	jobCtx, cancel := context.WithCancel(ctx)
+	p.cancels.Store(rid, cancel)
+
+	return jobCtx, func() {
+		p.cancels.Delete(rid)
+		cancel()
+	}
+}
+
+// Interrupted ctx 가 종료되어(프로세스 종료) 중단된 작업이면 resume 단계로 되돌리고 true
+// 실패로 기록하지 않으므로 재시작 시 RecoverJobs 가 resume 단계부터 이어서 처리
+func (p *ProcessedManager) Interrupted(ctx context.Context, jobs *job.Job, resume int) bool {
+	if ctx.Err() == nil {
 		return false
 	}
 
-	return v >= expected
+	slog.Warn("job interrupted by shutdown, resume after restart", "rid", jobs.GetRID(), "video_path", jobs.GetVideoPath(), "interrupted_step", jobs.GetStep(), "step", resume)
+	jobs.SetError("")
+	p.MarkProcessed(jobs, resume)
+	return true
+}
+
+// Cancel rid 에 해당하는 작업을 취소 상태로 기록하고 진행 중인 단계를 중단
+func (p *ProcessedManager) Cancel(rid string) (Record, error) {
+	record, ok := p.LoadByRID(rid)
+	if !ok {
+		return Record{}, ErrJobNotFound
+	}
+
+	unlock := p.lock(record.VideoPath)
+	defer unlock()
+
+	// 잠금을 얻는 동안 바뀐 상태를 다시 확인
+	record, ok = p.Load(record.VideoPath)
+	if !ok || record.RID != rid {
+		return Record{}, ErrJobNotFound
+	}
+	if IsFinished(record.Step) {
+		return record, ErrJobFinished
+	}
+
+	record.Step = JOB_CANCELLED
+	record.Error = "cancelled by request"
+	record.UpdatedAt = time.Now()
+	p.save(record)
+
+	if cancel, ok := p.cancels.Load(rid); ok {
+		cancel.(context.CancelFunc)()
+	}
+	return record, nil
+}
+
+// Forget 등록된 작업 상태를 제거, 등록 직후 작업을 넘기지 못한 경우 되돌리는 용도
+func (p *ProcessedManager) Forget(key string) {
+	unlock := p.lock(key)
+	defer unlock()
+
+	record, ok := p.Load(key)
+	if !ok {
+		return
+	}
+
+	p.memory.Delete(key)
+	p.rids.Delete(record.RID)
+	if err := p.store.Delete(key); err != nil {
+		slog.Error("failed delete job record", "rid", record.RID, "video_path", key, "error", err.Error())
+	}
+}
+
+// Revert 등록 직후 작업을 넘기지 못한 경우 jobs 의 등록만 되돌림, 이전 기록(prev)이 있으면 복원
+// 그 사이 다른 작업의 기록으로 바뀌었으면 그대로 둠
+func (p *ProcessedManager) Revert(jobs *job.Job, prev Record, hadPrev bool) {
+	key := jobs.GetVideoPath()
+	unlock := p.lock(key)
+	defer unlock()
+
+	record, ok := p.Load(key)
+	if !ok || record.RID != jobs.GetRID() {
+		return
+	}
+
+	p.rids.Delete(record.RID)
+	if hadPrev {
+		p.save(prev)
+		return
+	}
+
+	p.memory.Delete(key)
+	if err := p.store.Delete(key); err != nil {
+		slog.Error("failed delete job record", "rid", record.RID, "video_path", key, "error", err.Error())
+	}
+}
+
+func (p *ProcessedManager) lock(key string) func() {
+	return p.locks.lock(key)
+}
+
+func (p *ProcessedManager) save(record Record) {
+	p.memory.Store(record.VideoPath, record)
+	p.rids.Store(record.RID, record.VideoPath)
+	if err := p.store.Save(record); err != nil {
+		slog.Error("failed save job record", "rid", record.RID, "video_path", record.VideoPath, "step", record.Step, "error", err.Error())
+	}
 }
 
-func (p *ProcessedManager) MarkProcessed(key string, value int) {
-	p.memory.Store(key, value)
+func (p *ProcessedManager) Close() error {
+	return p.store.Close()
 }
@@ -0,0 +1,126 @@
+package process
+
+import (
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"video-ai-stt/internal/job"
+	"video-ai-stt/internal/media"
+)
+
+func newTestManager(t *testing.T) *ProcessedManager {
+	t.Helper()
+
+	manager, err := NewProcessedManager(newMemoryStore())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return manager
+}
+
+func TestMarkProcessedKeepsFinishedStep(t *testing.T) {
+	tests := []struct {
+		name     string
+		finished int
+		next     int
+		want     bool
+	}{
+		{name: "취소 후 다음 단계", finished: JOB_CANCELLED, next: REQUEST_GROQ_API_END, want: false},
+		{name: "취소 후 실패", finished: JOB_CANCELLED, next: REQUEST_GROQ_API_FAILED, want: false},
+		{name: "실패 후 재시작 단계", finished: EXTRACT_AUDIO_FAILED, next: WATCHER_FILE_REGISTER, want: false},
+		{name: "실패 정보 갱신", finished: EXTRACT_AUDIO_FAILED, next: EXTRACT_AUDIO_FAILED, want: true},
+		{name: "완료 후 이전 단계", finished: ALL_PROCESS_COMPLETE, next: GENERATE_SUBTITLE_START, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := newTestManager(t)
+			jobs := job.NewJob("/uploads/a.mp4", "a.mp4")
+			manager.MarkProcessed(jobs, EXTRACT_AUDIO_START)
+
+			if tt.finished == JOB_CANCELLED {
+				if _, err := manager.Cancel(jobs.GetRID()); err != nil {
+					t.Fatal(err)
+				}
+			} else {
+				manager.MarkProcessed(jobs, tt.finished)
+			}
+
+			if got := manager.MarkProcessed(jobs, tt.next); got != tt.want {
+				t.Errorf("MarkProcessed(%d) = %v, want %v", tt.next, got, tt.want)
+			}
+			record, _ := manager.Load(jobs.GetVideoPath())
+			if record.Step != tt.finished {
+				t.Errorf("recorded step = %d, want %d", record.Step, tt.finished)
+			}
+		})
+	}
+}
+
+// 같은 이름의 파일이 새 작업(다른 rid)으로 다시 등록되는 경우는 허용
+func TestMarkProcessedNewJob(t *testing.T) {
+	manager := newTestManager(t)
+	failed := job.NewJob("/uploads/a.mp4", "a.mp4")
+	manager.MarkProcessed(failed, EXTRACT_AUDIO_FAILED)
+
+	retry := job.NewJob("/uploads/a.mp4", "a.mp4")
+	if !manager.MarkProcessed(retry, WATCHER_FILE_REGISTER) {
+		t.Fatal("MarkProcessed() refused new job of same path")
+	}
+}
+
+func TestCancelRacesWithMarkProcessed(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		manager := newTestManager(t)
+		jobs := job.NewJob("/uploads/a.mp4", "a.mp4")
+		manager.MarkProcessed(jobs, REQUEST_GROQ_API_START)
+
+		var wg sync.WaitGroup
+		var cancelErr error
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_, cancelErr = manager.Cancel(jobs.GetRID())
+		}()
+		go func() {
+			defer wg.Done()
+			manager.MarkProcessed(jobs, REQUEST_GROQ_API_END)
+			manager.MarkProcessed(jobs, GENERATE_SUBTITLE_START)
+		}()
+		wg.Wait()
+
+		record, _ := manager.Load(jobs.GetVideoPath())
+		if cancelErr == nil && record.Step != JOB_CANCELLED {
+			t.Fatalf("cancelled job overwritten, step = %d", record.Step)
+		}
+	}
+}
+
+func TestMarkProcessedPersistsSilences(t *testing.T) {
+	store, err := newBoltStore(filepath.Join(t.TempDir(), "jobs.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	manager, err := NewProcessedManager(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jobs := job.NewJob("/uploads/a.mp4", "a.mp4")
+	silences := []media.Silence{{Start: 12.5, End: 13.2}, {Start: 600.1, End: 601}}
+	jobs.SetSilences(silences)
+	manager.MarkProcessed(jobs, EXTRACT_AUDIO_COMPLETE)
+
+	// 재시작 시 store 에서 다시 읽은 기록
+	restarted, err := NewProcessedManager(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	record, _ := restarted.Load(jobs.GetVideoPath())
+	if !reflect.DeepEqual(record.Silences, silences) {
+		t.Errorf("silences = %v, want %v", record.Silences, silences)
+	}
+}
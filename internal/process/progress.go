@@ -0,0 +1,37 @@
+package process
+
+import "time"
+
+// UploadProgress STT 공급자로 오디오를 업로드하는 진행 상황, 전사 중인 작업만 메모리에 유지
+type UploadProgress struct {
+	SentBytes  int64     `json:"sent_bytes"`
+	TotalBytes int64     `json:"total_bytes"`
+	Percent    float64   `json:"percent"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+func (p *ProcessedManager) SetUploadProgress(rid string, sent, total int64) {
+	progress := UploadProgress{
+		SentBytes:  sent,
+		TotalBytes: total,
+		UpdatedAt:  time.Now(),
+	}
+	if total > 0 {
+		progress.Percent = float64(sent*1000/total) / 10
+	}
+	p.progress.Store(rid, progress)
+}
+
+func (p *ProcessedManager) UploadProgress(rid string) (UploadProgress, bool) {
+	val, ok := p.progress.Load(rid)
+	if !ok {
+		return UploadProgress{}, false
+	}
+
+	progress, ok := val.(UploadProgress)
+	return progress, ok
+}
+
+func (p *ProcessedManager) ClearUploadProgress(rid string) {
+	p.progress.Delete(rid)
+}
@@ -0,0 +1,85 @@
+package process
+
+import (
+	"fmt"
+	"sync"
+	"time"
+	"video-ai-stt/config"
+	"video-ai-stt/internal/job"
+	"video-ai-stt/internal/media"
+)
+
+const (
+	STORE_TYPE_MEMORY = "memory"
+	STORE_TYPE_BOLT   = "bolt"
+)
+
+// Record 재시작 이후에도 유지되는 작업 상태
+type Record struct {
+	RID            string            `json:"rid"`
+	VideoPath      string            `json:"video_path"`
+	AudioPath      string            `json:"audio_path"`
+	TranscriptPath string            `json:"transcript_path,omitempty"`
+	Filename       string            `json:"filename"`
+	Step           int               `json:"step"`
+	Error          string            `json:"error,omitempty"`
+	Reason         string            `json:"reason,omitempty"`
+	FailedPath     string            `json:"failed_path,omitempty"`
+	Artifacts      map[string]string `json:"artifacts,omitempty"`
+	Metadata       job.Metadata      `json:"metadata"`
+	Media          *media.Info       `json:"media,omitempty"`
+	Silences       []media.Silence   `json:"silences,omitempty"`
+	Profile        config.Profile    `json:"profile"`
+	CreatedAt      time.Time         `json:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at"`
+}
+
+// Store 작업 상태 영속화 계층, key 는 영상 경로
+type Store interface {
+	Load() ([]Record, error)
+	Save(record Record) error
+	Delete(key string) error
+	Close() error
+}
+
+func NewStore(cfg config.Store) (Store, error) {
+	switch cfg.Type {
+	case STORE_TYPE_MEMORY:
+		return newMemoryStore(), nil
+	case STORE_TYPE_BOLT:
+		return newBoltStore(cfg.Path)
+	default:
+		return nil, fmt.Errorf("unsupported store type: %s", cfg.Type)
+	}
+}
+
+type memoryStore struct {
+	records *sync.Map
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{records: &sync.Map{}}
+}
+
+func (m *memoryStore) Load() ([]Record, error) {
+	var records []Record
+	m.records.Range(func(_, value any) bool {
+		records = append(records, value.(Record))
+		return true
+	})
+	return records, nil
+}
+
+func (m *memoryStore) Save(record Record) error {
+	m.records.Store(record.VideoPath, record)
+	return nil
+}
+
+func (m *memoryStore) Delete(key string) error {
+	m.records.Delete(key)
+	return nil
+}
+
+func (m *memoryStore) Close() error {
+	return nil
+}
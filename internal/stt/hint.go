@@ -0,0 +1,135 @@
+package stt
+
+import (
+	"strings"
+	"unicode/utf8"
+	"video-ai-stt/internal/job"
+)
+
+const (
+	// 토큰 수 추정 단위, 실제 tokenizer 보다 크게 잡아 공급자에서 잘리지 않도록 함
+	// ASCII 3 글자 = 1 토큰, 그 외(한글, 한자 등) 1 글자 = 2 토큰
+	TOKEN_UNITS          = 3
+	ASCII_RUNE_UNITS     = 1
+	NON_ASCII_RUNE_UNITS = 6
+
+	VOCABULARY_SEPARATOR = ", "
+)
+
+// Hints 전사 요청에 사용한 언어 힌트와 prompt, 결과 json 에 함께 기록
+type Hints struct {
+	Language string `json:"language,omitempty"`
+	// Prompt 용어 목록까지 합쳐 실제로 요청한 prompt
+	Prompt string `json:"prompt,omitempty"`
+	// PromptTokens prompt 의 추정 토큰 수
+	PromptTokens int      `json:"prompt_tokens,omitempty"`
+	Vocabulary   []string `json:"vocabulary,omitempty"`
+	// DroppedVocabulary 길이 제한으로 prompt 에 넣지 못한 용어
+	DroppedVocabulary []string `json:"dropped_vocabulary,omitempty"`
+	// PromptTruncated prompt 자체가 길이 제한을 넘어 앞부분을 잘라냄
+	PromptTruncated bool `json:"prompt_truncated,omitempty"`
+}
+
+// hints 작업 metadata > 프로필 > 전역 설정 순으로 언어와 prompt 를 정하고, 용어는 모두 합쳐 prompt 에 추가
+func (p *Processor) hints(jobs *job.Job) Hints {
+	metadata := jobs.GetMetadata()
+	profile := jobs.GetProfile()
+
+	hints := Hints{
+		Language: firstNonEmpty(jobs.GetLanguage(), p.cfg.Language),
+	}
+	prompt := firstNonEmpty(metadata.Prompt, profile.Prompt, p.cfg.Prompt)
+	vocabulary := mergeVocabulary(metadata.Vocabulary, profile.Vocabulary, p.cfg.Vocabulary)
+	hints.compile(prompt, vocabulary, p.cfg.PromptMaxTokens)
+	return hints
+}
+
+// compile maxTokens 안에서 prompt 뒤에 용어를 앞에서부터 추가, maxTokens 가 0 이면 제한 없음
+// prompt 만으로 제한을 넘으면 공급자와 같이 마지막 부분만 남기고 용어는 넣지 않음
+func (h *Hints) compile(prompt string, vocabulary []string, maxTokens int) {
+	prompt = strings.TrimSpace(prompt)
+	limit := maxTokens * TOKEN_UNITS
+
+	units := promptUnits(prompt)
+	if maxTokens > 0 && units > limit {
+		prompt, units = truncateHead(prompt, limit)
+		h.PromptTruncated = true
+	}
+
+	var builder strings.Builder
+	builder.WriteString(prompt)
+	for _, term := range vocabulary {
+		sep := VOCABULARY_SEPARATOR
+		switch {
+		case builder.Len() == 0:
+			sep = ""
+		case len(h.Vocabulary) == 0:
+			sep = " "
+		}
+
+		added := promptUnits(sep) + promptUnits(term)
+		if maxTokens > 0 && units+added > limit {
+			h.DroppedVocabulary = append(h.DroppedVocabulary, term)
+			continue
+		}
+
+		builder.WriteString(sep)
+		builder.WriteString(term)
+		units += added
+		h.Vocabulary = append(h.Vocabulary, term)
+	}
+
+	h.Prompt = builder.String()
+	h.PromptTokens = (units + TOKEN_UNITS - 1) / TOKEN_UNITS
+}
+
+// promptUnits 토큰 수 추정 단위 합계, 글자별로 더하므로 이어 붙인 문자열의 값은 각 값의 합과 같음
+func promptUnits(s string) int {
+	units := 0
+	for _, r := range s {
+		if r < utf8.RuneSelf {
+			units += ASCII_RUNE_UNITS
+		} else {
+			units += NON_ASCII_RUNE_UNITS
+		}
+	}
+	return units
+}
+
+// truncateHead limit 단위 안에 들어오도록 앞부분을 잘라낸 문자열과 단위 합계
+func truncateHead(s string, limit int) (string, int) {
+	units := promptUnits(s)
+	for units > limit && s != "" {
+		r, size := utf8.DecodeRuneInString(s)
+		units -= promptUnits(string(r))
+		s = s[size:]
+	}
+	s = strings.TrimSpace(s)
+	return s, promptUnits(s)
+}
+
+// mergeVocabulary 중복을 제외하고 앞의 목록을 우선하여 합침
+func mergeVocabulary(lists ...[]string) []string {
+	var merged []string
+	seen := map[string]bool{}
+	for _, list := range lists {
+		for _, term := range list {
+			term = strings.TrimSpace(term)
+			if term == "" || seen[term] {
+				continue
+			}
+			seen[term] = true
+			merged = append(merged, term)
+		}
+	}
+	return merged
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
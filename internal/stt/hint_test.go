@@ -0,0 +1,143 @@
+package stt
+
+import (
+	"reflect"
+	"testing"
+	"video-ai-stt/config"
+	"video-ai-stt/internal/job"
+)
+
+func TestCompile(t *testing.T) {
+	tests := []struct {
+		name       string
+		prompt     string
+		vocabulary []string
+		maxTokens  int
+		want       Hints
+	}{
+		{
+			name:       "no limit",
+			prompt:     " 회의록 ",
+			vocabulary: []string{"Kubernetes", "gRPC"},
+			want: Hints{
+				Prompt:       "회의록 Kubernetes, gRPC",
+				PromptTokens: 12,
+				Vocabulary:   []string{"Kubernetes", "gRPC"},
+			},
+		},
+		{
+			name:       "drop vocabulary over limit",
+			vocabulary: []string{"Kubernetes", "gRPC", "k8s"},
+			maxTokens:  5,
+			want: Hints{
+				Prompt:            "Kubernetes, k8s",
+				PromptTokens:      5,
+				Vocabulary:        []string{"Kubernetes", "k8s"},
+				DroppedVocabulary: []string{"gRPC"},
+			},
+		},
+		{
+			name:       "truncate prompt head",
+			prompt:     "hello world",
+			vocabulary: []string{"x"},
+			maxTokens:  2,
+			want: Hints{
+				Prompt:            "world",
+				PromptTokens:      2,
+				DroppedVocabulary: []string{"x"},
+				PromptTruncated:   true,
+			},
+		},
+		{
+			name:      "truncate hangul prompt",
+			prompt:    "가나다",
+			maxTokens: 2,
+			want: Hints{
+				Prompt:          "다",
+				PromptTokens:    2,
+				PromptTruncated: true,
+			},
+		},
+		{
+			name: "empty",
+			want: Hints{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Hints
+			got.compile(tt.prompt, tt.vocabulary, tt.maxTokens)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("compile() = %+v, want %+v", got, tt.want)
+			}
+			if tt.maxTokens > 0 && got.PromptTokens > tt.maxTokens {
+				t.Errorf("prompt tokens %d > max %d", got.PromptTokens, tt.maxTokens)
+			}
+		})
+	}
+}
+
+func TestPromptUnits(t *testing.T) {
+	tests := []struct {
+		s    string
+		want int
+	}{
+		{s: "", want: 0},
+		{s: "abc", want: 3},
+		{s: "한국어", want: 18},
+		{s: "a, 한", want: 9},
+	}
+
+	for _, tt := range tests {
+		if got := promptUnits(tt.s); got != tt.want {
+			t.Errorf("promptUnits(%q) = %d, want %d", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestTruncateHead(t *testing.T) {
+	tests := []struct {
+		s         string
+		limit     int
+		want      string
+		wantUnits int
+	}{
+		{s: "abc def", limit: 3, want: "def", wantUnits: 3},
+		{s: "가나다라", limit: 12, want: "다라", wantUnits: 12},
+		{s: "가나다라", limit: 11, want: "라", wantUnits: 6},
+		{s: "short", limit: 10, want: "short", wantUnits: 5},
+		{s: "abc", limit: 0, want: "", wantUnits: 0},
+	}
+
+	for _, tt := range tests {
+		got, units := truncateHead(tt.s, tt.limit)
+		if got != tt.want || units != tt.wantUnits {
+			t.Errorf("truncateHead(%q, %d) = %q, %d, want %q, %d", tt.s, tt.limit, got, units, tt.want, tt.wantUnits)
+		}
+	}
+}
+
+func TestMergeVocabulary(t *testing.T) {
+	got := mergeVocabulary([]string{"gRPC", " k8s "}, nil, []string{"k8s", "", "Kafka", "gRPC"})
+	want := []string{"gRPC", "k8s", "Kafka"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("mergeVocabulary() = %q, want %q", got, want)
+	}
+}
+
+func TestProcessorHintsPriority(t *testing.T) {
+	p := &Processor{cfg: config.STT{Language: "en", Prompt: "global", Vocabulary: []string{"global-term"}}}
+
+	jobs := job.NewJob("/uploads/a.mp4", "a.mp4")
+	jobs.SetProfile(config.Profile{Language: "ko", Prompt: "profile", Vocabulary: []string{"profile-term"}})
+	jobs.SetMetadata(job.Metadata{Prompt: "metadata", Vocabulary: []string{"job-term", "profile-term"}})
+
+	hints := p.hints(jobs)
+	if hints.Language != "ko" {
+		t.Errorf("language = %s, want profile language", hints.Language)
+	}
+	if hints.Prompt != "metadata job-term, profile-term, global-term" {
+		t.Errorf("prompt = %q", hints.Prompt)
+	}
+}
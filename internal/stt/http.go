@@ -0,0 +1,143 @@
+package stt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+	"video-ai-stt/internal/pipeline"
+	"video-ai-stt/internal/process"
+)
+
+// Field multipart/form-data 일반 필드
+type Field struct {
+	Name  string
+	Value string
+}
+
+// Request 멀티파트 업로드 방식의 transcription 요청
+type Request struct {
+	Endpoint string
+	APIToken string
+	Fields   []Field
+	FilePath string
+	// Progress 업로드 진행 상황, 재시도하면 0 부터 다시 전달
+	Progress ProgressFunc
+}
+
+// PostMultipart 재시도 정책에 따라 요청을 전송하고 200 응답 본문을 반환
+func PostMultipart(ctx context.Context, logger *slog.Logger, policy RetryPolicy, r Request) ([]byte, error) {
+
+	requestBody, err := newMultipartBody(r.Fields, r.FilePath)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidRequest, err)
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= policy.maxAttempts; attempt++ {
+		body, err := post(ctx, logger, attempt, policy.requestTimeout, r, requestBody)
+		if err == nil {
+			return body, nil
+		}
+		lastErr = err
+
+		if !IsRetryable(err) {
+			logger.Error("stt audio transcriptions call non-retryable error", "attempt", attempt, "err", err.Error())
+			break
+		}
+
+		if attempt == policy.maxAttempts {
+			break
+		}
+
+		wait := policy.wait(attempt, err)
+		logger.Warn("stt audio transcriptions call retry", "attempt", attempt, "max_attempts", policy.maxAttempts, "wait", wait.String(), "err", err.Error())
+		if err := sleep(ctx, wait); err != nil {
+			return nil, fmt.Errorf("stopped retry: %w, last error: %w", err, lastErr)
+		}
+	}
+
+	return nil, lastErr
+}
+
+// sleep ctx 가 종료되면 대기를 멈추고 ctx.Err() 반환
+func sleep(ctx context.Context, wait time.Duration) error {
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func post(ctx context.Context, logger *slog.Logger, attempt int, timeout time.Duration, r Request, requestBody *multipartBody) ([]byte, error) {
+
+	// 요청 한 번의 제한 시간, 응답 본문을 다 읽을 때까지 유지
+	attemptCtx, cancel := pipeline.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	body, err := send(attemptCtx, logger, attempt, r, requestBody)
+	if err != nil && ctx.Err() == nil && errors.Is(attemptCtx.Err(), context.DeadlineExceeded) {
+		return nil, fmt.Errorf("%w: exceeded %s: %w", ErrRequestTimeout, timeout, err)
+	}
+	return body, err
+}
+
+func send(ctx context.Context, logger *slog.Logger, attempt int, r Request, requestBody *multipartBody) ([]byte, error) {
+
+	reader, err := requestBody.open(r.Progress)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidRequest, err)
+	}
+
+	// HTTP 요청 생성, ctx 가 종료되면 전송 중인 요청도 중단
+	// 본문은 디스크에서 스트리밍하며 길이를 미리 계산해 Content-Length 로 전송
+	req, err := http.NewRequestWithContext(ctx, "POST", r.Endpoint, reader)
+	if err != nil {
+		reader.Close()
+		return nil, fmt.Errorf("%w: failed creating request: %w", ErrInvalidRequest, err)
+	}
+	req.ContentLength = requestBody.size
+	req.GetBody = func() (io.ReadCloser, error) {
+		return requestBody.open(r.Progress)
+	}
+
+	// 인증 및 헤더 설정
+	if r.APIToken != "" {
+		req.Header.Set("Authorization", "Bearer "+r.APIToken)
+	}
+	req.Header.Set("Content-Type", requestBody.contentType)
+
+	logger.Info("stt audio transcriptions call request", "step", process.REQUEST_GROQ_API_START, "attempt", attempt, "content_length", requestBody.size)
+
+	// 요청 전송
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed sending request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// 응답 읽기
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &APIError{
+			StatusCode: resp.StatusCode,
+			Body:       string(body),
+			RetryAfter: retryAfter(resp.StatusCode, resp.Header),
+		}
+	}
+
+	logger.Info("stt audio transcriptions call response", "step", process.REQUEST_GROQ_API_END, "attempt", attempt, "status_code", resp.StatusCode, "body", string(body))
+	return body, nil
+}
@@ -0,0 +1,95 @@
+package stt
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+)
+
+// ProgressFunc 업로드한 바이트 수와 전체 요청 본문 크기를 전달받는 콜백
+type ProgressFunc func(sent, total int64)
+
+// multipartBody 오디오 파일을 메모리에 올리지 않고 디스크에서 바로 읽는 multipart/form-data 본문
+// 일반 필드와 파일 파트 헤더(head), 파일 내용, 종료 boundary(tail) 순서로 전송하며 전체 길이를 미리 계산
+type multipartBody struct {
+	head        []byte
+	tail        []byte
+	filePath    string
+	size        int64
+	contentType string
+}
+
+func newMultipartBody(fields []Field, filePath string) (*multipartBody, error) {
+
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed stat audio file: %w", err)
+	}
+
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+
+	for _, field := range fields {
+		if err := writer.WriteField(field.Name, field.Value); err != nil {
+			return nil, fmt.Errorf("failed write field %s, err: %w", field.Name, err)
+		}
+	}
+
+	if _, err := writer.CreateFormFile("file", filepath.Base(filePath)); err != nil {
+		return nil, fmt.Errorf("failed creating form file: %w", err)
+	}
+	head := bytes.Clone(buf.Bytes())
+	buf.Reset()
+
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("failed closing writer: %w", err)
+	}
+	tail := bytes.Clone(buf.Bytes())
+
+	return &multipartBody{
+		head:        head,
+		tail:        tail,
+		filePath:    filePath,
+		size:        int64(len(head)) + info.Size() + int64(len(tail)),
+		contentType: writer.FormDataContentType(),
+	}, nil
+}
+
+// open 전송할 때마다(재시도 포함) 파일을 새로 열어 본문 reader 생성
+func (b *multipartBody) open(progress ProgressFunc) (io.ReadCloser, error) {
+	file, err := os.Open(b.filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed opening audio file: %w", err)
+	}
+
+	return &bodyReader{
+		Reader:   io.MultiReader(bytes.NewReader(b.head), file, bytes.NewReader(b.tail)),
+		file:     file,
+		total:    b.size,
+		progress: progress,
+	}, nil
+}
+
+type bodyReader struct {
+	io.Reader
+	file     *os.File
+	sent     int64
+	total    int64
+	progress ProgressFunc
+}
+
+func (r *bodyReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	if n > 0 && r.progress != nil {
+		r.sent += int64(n)
+		r.progress(r.sent, r.total)
+	}
+	return n, err
+}
+
+func (r *bodyReader) Close() error {
+	return r.file.Close()
+}
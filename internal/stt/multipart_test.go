@@ -0,0 +1,183 @@
+package stt
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func writeAudio(t *testing.T, name string, size int) (string, []byte) {
+	t.Helper()
+
+	content := bytes.Repeat([]byte("flac"), size/4+1)[:size]
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path, content
+}
+
+// readMultipart contentType 의 boundary 로 본문을 파싱하여 필드와 file 파트 내용을 반환
+func readMultipart(t *testing.T, contentType string, body []byte) (map[string]string, string, []byte) {
+	t.Helper()
+
+	_, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fields := map[string]string{}
+	var filename string
+	var file []byte
+	reader := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(part)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if part.FormName() == "file" {
+			filename, file = part.FileName(), data
+			continue
+		}
+		fields[part.FormName()] = string(data)
+	}
+	return fields, filename, file
+}
+
+func TestMultipartBody(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields []Field
+		size   int
+	}{
+		{name: "no fields", size: 10},
+		{name: "empty file", fields: []Field{{Name: "model", Value: "whisper-large-v3"}}, size: 0},
+		{
+			name: "unicode and multiline fields",
+			fields: []Field{
+				{Name: "model", Value: "whisper-large-v3-turbo"},
+				{Name: "language", Value: "ko"},
+				{Name: "prompt", Value: "대학 강의 녹화본입니다.\nKubernetes, gRPC"},
+			},
+			size: 100_003,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, content := writeAudio(t, "강의 1.flac", tt.size)
+
+			body, err := newMultipartBody(tt.fields, path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var sent, total int64
+			reader, err := body.open(func(s, tl int64) { sent, total = s, tl })
+			if err != nil {
+				t.Fatal(err)
+			}
+			data, err := io.ReadAll(reader)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := reader.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			if int64(len(data)) != body.size {
+				t.Fatalf("body length = %d, precomputed size = %d", len(data), body.size)
+			}
+			if tt.size > 0 && (sent != body.size || total != body.size) {
+				t.Errorf("progress = %d/%d, want %d/%d", sent, total, body.size, body.size)
+			}
+
+			fields, filename, file := readMultipart(t, body.contentType, data)
+			for _, field := range tt.fields {
+				if fields[field.Name] != field.Value {
+					t.Errorf("field %s = %q, want %q", field.Name, fields[field.Name], field.Value)
+				}
+			}
+			if filename != "강의 1.flac" || !bytes.Equal(file, content) {
+				t.Errorf("file part = %q, %d bytes, want %d bytes", filename, len(file), len(content))
+			}
+
+			// 재시도 시 같은 본문을 다시 읽을 수 있어야 함
+			again, err := body.open(nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer again.Close()
+			if data2, _ := io.ReadAll(again); !bytes.Equal(data, data2) {
+				t.Error("reopened body differs")
+			}
+		})
+	}
+}
+
+func TestMultipartBodyMissingFile(t *testing.T) {
+	if _, err := newMultipartBody(nil, filepath.Join(t.TempDir(), "missing.flac")); err == nil {
+		t.Error("newMultipartBody() accepted missing file")
+	}
+}
+
+func TestPostMultipartContentLength(t *testing.T) {
+	path, content := writeAudio(t, "a.flac", 64*1024)
+
+	var mu sync.Mutex
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+		if r.ContentLength != int64(len(data)) || len(r.TransferEncoding) > 0 {
+			t.Errorf("content length = %d, received %d bytes, transfer encoding %v", r.ContentLength, len(data), r.TransferEncoding)
+		}
+		if _, _, file := readMultipart(t, r.Header.Get("Content-Type"), data); !bytes.Equal(file, content) {
+			t.Error("uploaded file differs")
+		}
+
+		mu.Lock()
+		attempts++
+		first := attempts == 1
+		mu.Unlock()
+		if first {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		io.WriteString(w, `{"text":"ok"}`)
+	}))
+	defer server.Close()
+
+	policy := RetryPolicy{maxAttempts: 2, baseBackoff: time.Millisecond}
+	body, err := PostMultipart(context.Background(), slog.Default(), policy, Request{
+		Endpoint: server.URL,
+		Fields:   []Field{{Name: "model", Value: "whisper"}},
+		FilePath: path,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(body), "ok") || attempts != 2 {
+		t.Errorf("body = %s, attempts = %d", body, attempts)
+	}
+}
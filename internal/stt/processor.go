@@ -0,0 +1,170 @@
+package stt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"sync/atomic"
+	"video-ai-stt/config"
+	"video-ai-stt/internal/failure"
+	"video-ai-stt/internal/job"
+	"video-ai-stt/internal/pipeline"
+	"video-ai-stt/internal/process"
+)
+
+// Processor audioCh 로 들어온 작업을 Transcriber 로 변환하고 transcript 를 저장해 다음 단계로 넘기는 파이프라인 단계
+type Processor struct {
+	cfg         config.STT
+	transcriber Transcriber
+	processed   *process.ProcessedManager
+	failure     *failure.Recorder
+}
+
+func NewProcessor(cfg config.STT, transcriber Transcriber, manager *process.ProcessedManager, recorder *failure.Recorder) *Processor {
+	return &Processor{
+		cfg:         cfg,
+		transcriber: transcriber,
+		processed:   manager,
+		failure:     recorder,
+	}
+}
+
+func (p *Processor) Process(ctx context.Context, audioCh <-chan *job.Job, transcriptCh chan<- *job.Job) error {
+
+	pool := pipeline.NewPool(p.cfg.Concurrency)
+
+LOOP:
+	for {
+		// 실행 자리가 생길 때까지 audioCh 를 읽지 않음
+		if !pool.Acquire(ctx) {
+			slog.Debug("stt processor goroutine close")
+			break LOOP
+		}
+
+		select {
+		case <-ctx.Done():
+			pool.Release()
+			slog.Debug("stt processor goroutine close")
+			break LOOP
+		case jobs, ok := <-audioCh:
+			if !ok {
+				pool.Release()
+				slog.Debug("stt processor audioCh closed, breaking loop")
+				break LOOP
+			}
+			logger := slog.With("rid", jobs.GetRID(), "video_path", jobs.GetVideoPath(), "audio_path", jobs.GetAudioPath(), "provider", p.transcriber.Name())
+			logger.Debug("stt processor audioCh receive", "step", process.REQUEST_GROQ_API_START, "queue_depth", len(audioCh), "running", pool.Running())
+			alreadyProcess := p.processed.IsProcessed(jobs.GetVideoPath(), process.REQUEST_GROQ_API_START)
+			if alreadyProcess {
+				pool.Release()
+				continue
+			}
+
+			pool.Go(func() {
+				jobCtx, cancel := p.processed.JobContext(ctx, jobs.GetRID())
+				defer cancel()
+
+				if !p.processed.MarkProcessed(jobs, process.REQUEST_GROQ_API_START) {
+					logger.Info("skip stt, job cancelled or finished", "step", process.REQUEST_GROQ_API_START)
+					return
+				}
+				transcript, err := p.transcribe(jobCtx, jobs)
+				if err != nil {
+					if p.processed.Interrupted(ctx, jobs, process.EXTRACT_AUDIO_COMPLETE) {
+						return
+					}
+					logger.Error("failed request stt api", "err", err.Error(), "step", process.REQUEST_GROQ_API_FAILED)
+					p.failure.Fail(jobs, process.REQUEST_GROQ_API_FAILED, err)
+					return
+				}
+
+				transcriptPath := p.TranscriptPath(jobs)
+				if err := SaveTranscript(transcriptPath, transcript); err != nil {
+					logger.Error("failed save transcript", "transcript_path", transcriptPath, "err", err.Error(), "step", process.REQUEST_GROQ_API_FAILED)
+					p.failure.Fail(jobs, process.REQUEST_GROQ_API_FAILED, err)
+					return
+				}
+
+				jobs.SetTranscriptPath(transcriptPath)
+				if !p.processed.MarkProcessed(jobs, process.REQUEST_GROQ_API_END) {
+					logger.Info("job cancelled during stt", "step", process.REQUEST_GROQ_API_END)
+					return
+				}
+				logger.Info("end stt goroutine", "transcript_path", transcriptPath, "step", process.REQUEST_GROQ_API_END, "transcript_queue_depth", len(transcriptCh))
+
+				select {
+				case transcriptCh <- jobs:
+				case <-ctx.Done():
+					// 전사 완료 상태로 남겨 재시작 시 저장된 transcript 로 자막만 생성
+					logger.Warn("transcript queue closed by shutdown, resume after restart", "step", process.REQUEST_GROQ_API_END)
+				}
+			})
+		}
+	}
+
+	slog.Debug("waiting for all stt goroutines to finish")
+	pool.Wait()
+	slog.Debug("all stt goroutines completed")
+
+	// 남은 작업을 모두 넘긴 뒤 자막 생성 단계에 종료를 알림
+	close(transcriptCh)
+
+	return nil
+}
+
+// transcribe STT_TRANSCRIBE_TIMEOUT 안에 전사, 요청마다 STT_REQUEST_TIMEOUT 이 지나면 재시도
+// 재시도까지 모두 제한 시간을 넘기면 failure.ErrTimeout
+func (p *Processor) transcribe(ctx context.Context, jobs *job.Job) (*Transcript, error) {
+	ctx, cancel := pipeline.WithTimeout(ctx, p.cfg.Timeout)
+	defer cancel()
+
+	defer p.processed.ClearUploadProgress(jobs.GetRID())
+
+	hints := p.hints(jobs)
+	logger := slog.With("rid", jobs.GetRID(), "audio_path", jobs.GetAudioPath())
+	logger.Info("stt request hints", "language", hints.Language, "prompt_tokens", hints.PromptTokens, "vocabulary_count", len(hints.Vocabulary))
+	if hints.PromptTruncated || len(hints.DroppedVocabulary) > 0 {
+		logger.Warn("stt prompt exceeds token limit", "prompt_max_tokens", p.cfg.PromptMaxTokens, "prompt_truncated", hints.PromptTruncated, "dropped_vocabulary", hints.DroppedVocabulary)
+	}
+
+	transcript, err := p.transcriber.Transcribe(ctx, jobs.GetAudioPath(), Options{
+		RID:      jobs.GetRID(),
+		Model:    jobs.GetProfile().Model,
+		Language: hints.Language,
+		Prompt:   hints.Prompt,
+		Silences: jobs.GetSilences(),
+		Progress: p.uploadProgress(jobs),
+	})
+	if err != nil {
+		if errors.Is(err, ErrRequestTimeout) {
+			return nil, fmt.Errorf("%w: %w", failure.ErrTimeout, err)
+		}
+		return nil, failure.Timeout(ctx, p.cfg.Timeout, err)
+	}
+
+	transcript.Hints = &hints
+	return transcript, nil
+}
+
+// uploadProgress 업로드 진행 상황을 작업 상태(api 조회)에 반영하고 10% 단위로 로그 기록
+func (p *Processor) uploadProgress(jobs *job.Job) ProgressFunc {
+	logged := atomic.Int64{}
+	return func(sent, total int64) {
+		p.processed.SetUploadProgress(jobs.GetRID(), sent, total)
+
+		if total <= 0 {
+			return
+		}
+		decile := sent * 10 / total
+		if prev := logged.Load(); decile > prev && logged.CompareAndSwap(prev, decile) {
+			slog.Debug("stt upload progress", "rid", jobs.GetRID(), "audio_path", jobs.GetAudioPath(), "sent_bytes", sent, "total_bytes", total, "percent", decile*10)
+		}
+	}
+}
+
+// TranscriptPath stt 결과(정규화된 transcript)가 저장되는 경로
+func (p *Processor) TranscriptPath(jobs *job.Job) string {
+	return filepath.Join(p.cfg.TranscriptDir, jobs.OutputName()+TRANSCRIPT_EXT)
+}
@@ -0,0 +1,134 @@
+package stt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+	"video-ai-stt/config"
+)
+
+var (
+	ErrInvalidRequest  = errors.New("invalid stt request")
+	ErrInvalidResponse = errors.New("invalid stt response")
+	// ErrRequestTimeout 요청 한 번이 STT_REQUEST_TIMEOUT 안에 끝나지 않음 (업로드 또는 응답 지연), 재시도 대상
+	ErrRequestTimeout = errors.New("stt request timeout")
+)
+
+// APIError stt api 가 200 이외의 상태 코드로 응답한 경우
+type APIError struct {
+	StatusCode int
+	Body       string
+	RetryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("failed curl stt api, status_code: %d, body: %s", e.StatusCode, e.Body)
+}
+
+type RetryPolicy struct {
+	maxAttempts    int
+	baseBackoff    time.Duration
+	maxBackoff     time.Duration
+	jitter         float64
+	requestTimeout time.Duration
+}
+
+func NewRetryPolicy(cfg config.STT) RetryPolicy {
+	policy := RetryPolicy{
+		maxAttempts: cfg.RetryMaxAttempts,
+		baseBackoff: cfg.RetryBaseBackoff,
+		maxBackoff:  cfg.RetryMaxBackoff,
+		jitter:      cfg.RetryJitter,
+
+		requestTimeout: cfg.RequestTimeout,
+	}
+	if policy.maxAttempts < 1 {
+		policy.maxAttempts = 1
+	}
+	return policy
+}
+
+// wait 서버가 알려준 대기 시간(Retry-After, rate limit reset)이 있으면 우선하고, 없으면 exponential backoff
+func (p RetryPolicy) wait(attempt int, err error) time.Duration {
+	backoff := p.backoff(attempt)
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > backoff {
+		return apiErr.RetryAfter
+	}
+	return backoff
+}
+
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	backoff := float64(p.baseBackoff) * math.Pow(2, float64(attempt-1))
+	if p.maxBackoff > 0 && backoff > float64(p.maxBackoff) {
+		backoff = float64(p.maxBackoff)
+	}
+
+	if p.jitter > 0 {
+		backoff += backoff * p.jitter * (rand.Float64()*2 - 1)
+	}
+	return time.Duration(backoff)
+}
+
+func IsRetryable(err error) bool {
+	if errors.Is(err, ErrInvalidRequest) || errors.Is(err, ErrInvalidResponse) {
+		return false
+	}
+	if errors.Is(err, ErrRequestTimeout) {
+		return true
+	}
+	// 종료, 취소 또는 단계 제한 시간 초과로 중단된 요청
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return isRetryableStatus(apiErr.StatusCode)
+	}
+
+	// 네트워크 오류
+	return true
+}
+
+// isRetryableStatus 400, 401, 403, 404, 413, 422 등 요청 자체의 문제는 재시도하지 않음
+func isRetryableStatus(statusCode int) bool {
+	switch statusCode {
+	case http.StatusRequestTimeout, http.StatusConflict, http.StatusTooEarly, http.StatusTooManyRequests:
+		return true
+	}
+	return statusCode >= http.StatusInternalServerError
+}
+
+// retryAfter Retry-After 헤더와 429 응답의 rate limit 헤더(x-ratelimit-reset-*) 중 가장 긴 대기 시간
+func retryAfter(statusCode int, header http.Header) time.Duration {
+	var wait time.Duration
+
+	if value := header.Get("Retry-After"); value != "" {
+		if seconds, err := strconv.Atoi(value); err == nil {
+			wait = time.Duration(seconds) * time.Second
+		} else if at, err := http.ParseTime(value); err == nil {
+			wait = time.Until(at)
+		}
+	}
+
+	if statusCode != http.StatusTooManyRequests {
+		return wait
+	}
+
+	for _, key := range []string{"x-ratelimit-reset-requests", "x-ratelimit-reset-tokens"} {
+		// ex) "2m59.56s", "7.66s"
+		reset, err := time.ParseDuration(header.Get(key))
+		if err == nil && reset > wait {
+			wait = reset
+		}
+	}
+
+	return wait
+}
@@ -0,0 +1,149 @@
+package stt
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestIsRetryableStatus(t *testing.T) {
+	tests := []struct {
+		statusCode int
+		want       bool
+	}{
+		{statusCode: http.StatusBadRequest, want: false},
+		{statusCode: http.StatusUnauthorized, want: false},
+		{statusCode: http.StatusForbidden, want: false},
+		{statusCode: http.StatusNotFound, want: false},
+		{statusCode: http.StatusRequestTimeout, want: true},
+		{statusCode: http.StatusConflict, want: true},
+		{statusCode: http.StatusRequestEntityTooLarge, want: false},
+		{statusCode: http.StatusUnprocessableEntity, want: false},
+		{statusCode: http.StatusTooEarly, want: true},
+		{statusCode: http.StatusTooManyRequests, want: true},
+		{statusCode: http.StatusInternalServerError, want: true},
+		{statusCode: http.StatusBadGateway, want: true},
+		{statusCode: http.StatusServiceUnavailable, want: true},
+	}
+
+	for _, tt := range tests {
+		if got := isRetryableStatus(tt.statusCode); got != tt.want {
+			t.Errorf("isRetryableStatus(%d) = %v, want %v", tt.statusCode, got, tt.want)
+		}
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "invalid request", err: fmt.Errorf("%w: empty file", ErrInvalidRequest), want: false},
+		{name: "invalid response", err: fmt.Errorf("%w: bad json", ErrInvalidResponse), want: false},
+		{name: "request timeout", err: fmt.Errorf("%w: 10m", ErrRequestTimeout), want: true},
+		{name: "cancelled", err: context.Canceled, want: false},
+		{name: "stage deadline", err: context.DeadlineExceeded, want: false},
+		{name: "rate limited", err: &APIError{StatusCode: http.StatusTooManyRequests}, want: true},
+		{name: "payload too large", err: fmt.Errorf("wrapped: %w", &APIError{StatusCode: http.StatusRequestEntityTooLarge}), want: false},
+		{name: "network", err: fmt.Errorf("connection reset by peer"), want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRetryable(tt.err); got != tt.want {
+				t.Errorf("IsRetryable(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		header     map[string]string
+		want       time.Duration
+	}{
+		{name: "no header", statusCode: http.StatusServiceUnavailable, want: 0},
+		{name: "retry-after seconds", statusCode: http.StatusServiceUnavailable, header: map[string]string{"Retry-After": "7"}, want: 7 * time.Second},
+		{name: "invalid retry-after", statusCode: http.StatusServiceUnavailable, header: map[string]string{"Retry-After": "soon"}, want: 0},
+		{
+			name:       "rate limit reset ignored for 5xx",
+			statusCode: http.StatusServiceUnavailable,
+			header:     map[string]string{"x-ratelimit-reset-requests": "2m59.56s"},
+			want:       0,
+		},
+		{
+			name:       "longest rate limit reset",
+			statusCode: http.StatusTooManyRequests,
+			header: map[string]string{
+				"Retry-After":                "3",
+				"x-ratelimit-reset-requests": "2m59.56s",
+				"x-ratelimit-reset-tokens":   "7.66s",
+			},
+			want: 2*time.Minute + 59560*time.Millisecond,
+		},
+		{
+			name:       "retry-after longer than reset",
+			statusCode: http.StatusTooManyRequests,
+			header:     map[string]string{"Retry-After": "30", "x-ratelimit-reset-tokens": "7.66s"},
+			want:       30 * time.Second,
+		},
+		{
+			name:       "invalid reset",
+			statusCode: http.StatusTooManyRequests,
+			header:     map[string]string{"x-ratelimit-reset-tokens": "7"},
+			want:       0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			for key, value := range tt.header {
+				header.Set(key, value)
+			}
+			if got := retryAfter(tt.statusCode, header); got != tt.want {
+				t.Errorf("retryAfter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryAfterHTTPDate(t *testing.T) {
+	header := http.Header{}
+	header.Set("Retry-After", time.Now().Add(time.Minute).UTC().Format(http.TimeFormat))
+
+	got := retryAfter(http.StatusServiceUnavailable, header)
+	if got <= 58*time.Second || got > time.Minute {
+		t.Errorf("retryAfter(http-date) = %v, want about 1m", got)
+	}
+}
+
+func TestRetryPolicyWait(t *testing.T) {
+	policy := RetryPolicy{baseBackoff: time.Second, maxBackoff: 5 * time.Second}
+
+	tests := []struct {
+		name    string
+		attempt int
+		err     error
+		want    time.Duration
+	}{
+		{name: "first backoff", attempt: 1, err: fmt.Errorf("network"), want: time.Second},
+		{name: "exponential", attempt: 3, err: fmt.Errorf("network"), want: 4 * time.Second},
+		{name: "max backoff", attempt: 10, err: fmt.Errorf("network"), want: 5 * time.Second},
+		{name: "server wait is longer", attempt: 1, err: &APIError{StatusCode: 429, RetryAfter: 30 * time.Second}, want: 30 * time.Second},
+		{name: "backoff is longer", attempt: 3, err: &APIError{StatusCode: 503, RetryAfter: time.Second}, want: 4 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy.wait(tt.attempt, tt.err); got != tt.want {
+				t.Errorf("wait(%d) = %v, want %v", tt.attempt, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package stt
+
+import (
+	"context"
+	"video-ai-stt/internal/media"
+)
+
+const (
+	PROVIDER_GROQ    = "groq"
+	PROVIDER_OPENAI  = "openai"
+	PROVIDER_WHISPER = "whisper"
+)
+
+// Transcriber 음성 파일을 받아 정규화된 Transcript 를 돌려주는 STT 공급자
+type Transcriber interface {
+	Name() string
+	Transcribe(ctx context.Context, audioPath string, opts Options) (*Transcript, error)
+}
+
+type Options struct {
+	RID   string
+	Model string
+	// Language ISO 639-1 언어 힌트 (ko, en ...), 비어 있으면 공급자가 자동 감지
+	Language string
+	// Prompt 용어, 표기, 문체를 맞추기 위한 힌트
+	Prompt string
+	// Silences 오디오를 나눠야 할 때 자를 위치로 사용하는 무음 구간
+	Silences []media.Silence
+	// Progress 오디오 업로드 진행 상황
+	Progress ProgressFunc
+}
+
+// Fields 공급자 공통 요청 필드 (language, prompt) 중 값이 있는 항목
+func (o Options) Fields() []Field {
+	var fields []Field
+	if o.Language != "" {
+		fields = append(fields, Field{Name: "language", Value: o.Language})
+	}
+	if o.Prompt != "" {
+		fields = append(fields, Field{Name: "prompt", Value: o.Prompt})
+	}
+	return fields
+}
+
+// Transcript 공급자와 무관하게 정규화된 STT 결과
+type Transcript struct {
+	Provider string    `json:"provider"`
+	Model    string    `json:"model"`
+	Language string    `json:"language"`
+	Duration float64   `json:"duration"`
+	Text     string    `json:"text"`
+	Segments []Segment `json:"segments"`
+	Words    []Word    `json:"words,omitempty"`
+	// Hints 요청에 사용한 언어 힌트, prompt, 용어 목록
+	Hints *Hints `json:"hints,omitempty"`
+}
+
+type Segment struct {
+	ID           int64   `json:"id"`
+	Start        float64 `json:"start"`
+	End          float64 `json:"end"`
+	Text         string  `json:"text"`
+	AvgLogProb   float64 `json:"avg_logprob"`
+	NoSpeechProb float64 `json:"no_speech_prob"`
+}
+
+type Word struct {
+	Word  string  `json:"word"`
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+}
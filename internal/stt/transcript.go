@@ -0,0 +1,39 @@
+package stt
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+const TRANSCRIPT_EXT = ".transcript.json"
+
+// SaveTranscript 재시작 이후 자막만 다시 생성할 수 있도록 transcript 를 파일로 저장
+func SaveTranscript(path string, transcript *Transcript) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed creating transcript file: %w", err)
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ") // 들여쓰기를 위해 설정
+	if err := encoder.Encode(transcript); err != nil {
+		return fmt.Errorf("failed encoding transcript: %w", err)
+	}
+	return nil
+}
+
+func LoadTranscript(path string) (*Transcript, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed opening transcript file: %w", err)
+	}
+	defer file.Close()
+
+	transcript := &Transcript{}
+	if err := json.NewDecoder(file).Decode(transcript); err != nil {
+		return nil, fmt.Errorf("failed decoding transcript file: %w", err)
+	}
+	return transcript, nil
+}
@@ -0,0 +1,51 @@
+package subtitle
+
+import (
+	"strings"
+	"video-ai-stt/internal/hangul"
+	"video-ai-stt/internal/stt"
+)
+
+// Cue 자막 형식과 무관한 한 구간의 자막
+type Cue struct {
+	Index int      `json:"index"`
+	Start float64  `json:"start"`
+	End   float64  `json:"end"`
+	Lines []string `json:"lines"`
+	// Settings 형식별 표시 설정 (ex: vtt "line:90% align:center")
+	Settings string `json:"-"`
+}
+
+func (c Cue) Text() string {
+	return strings.Join(c.Lines, "\n")
+}
+
+// Document writer 에 전달되는 자막 문서
+type Document struct {
+	RID        string
+	Transcript *stt.Transcript
+	Cues       []Cue
+}
+
+// NewDocument transcript 의 세그먼트를 그대로 cue 로 변환
+func NewDocument(rid string, transcript *stt.Transcript) *Document {
+	doc := &Document{
+		RID:        rid,
+		Transcript: transcript,
+	}
+
+	for _, segment := range transcript.Segments {
+		text := hangul.Normalize(segment.Text)
+		if text == "" {
+			continue
+		}
+
+		doc.Cues = append(doc.Cues, Cue{
+			Index: len(doc.Cues) + 1,
+			Start: segment.Start,
+			End:   segment.End,
+			Lines: []string{text},
+		})
+	}
+	return doc
+}
@@ -0,0 +1,218 @@
+package subtitle
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"video-ai-stt/config"
+	"video-ai-stt/internal/failure"
+	"video-ai-stt/internal/job"
+	"video-ai-stt/internal/pipeline"
+	"video-ai-stt/internal/process"
+	"video-ai-stt/internal/stt"
+	"video-ai-stt/utils"
+)
+
+// Generator transcriptCh 로 들어온 작업의 transcript 를 설정된 형식의 자막 파일로 기록하는 파이프라인 단계
+type Generator struct {
+	cfg       config.Subtitle
+	writers   map[string]Writer
+	rules     Rules
+	processed *process.ProcessedManager
+	failure   *failure.Recorder
+}
+
+func NewGenerator(cfg config.Subtitle, manager *process.ProcessedManager, recorder *failure.Recorder) (*Generator, error) {
+	writers := make(map[string]Writer, len(cfg.OutputFormats))
+	for _, format := range cfg.OutputFormats {
+		writer, err := Lookup(format)
+		if err != nil {
+			return nil, err
+		}
+		writers[format] = writer
+	}
+
+	return &Generator{
+		cfg:       cfg,
+		writers:   writers,
+		rules:     NewRules(cfg),
+		processed: manager,
+		failure:   recorder,
+	}, nil
+}
+
+func (g *Generator) Process(ctx context.Context, transcriptCh <-chan *job.Job) error {
+
+	pool := pipeline.NewPool(g.cfg.Concurrency)
+
+LOOP:
+	for {
+		// 실행 자리가 생길 때까지 transcriptCh 를 읽지 않음
+		if !pool.Acquire(ctx) {
+			slog.Debug("subtitle generator goroutine close")
+			break LOOP
+		}
+
+		select {
+		case <-ctx.Done():
+			pool.Release()
+			slog.Debug("subtitle generator goroutine close")
+			break LOOP
+		case jobs, ok := <-transcriptCh:
+			if !ok {
+				pool.Release()
+				slog.Debug("subtitle generator transcriptCh closed, breaking loop")
+				break LOOP
+			}
+
+			alreadyProcess := g.processed.IsProcessed(jobs.GetVideoPath(), process.GENERATE_SUBTITLE_START)
+			if alreadyProcess {
+				pool.Release()
+				continue
+			}
+
+			queueDepth := len(transcriptCh)
+			pool.Go(func() {
+				jobCtx, cancel := g.processed.JobContext(ctx, jobs.GetRID())
+				defer cancel()
+
+				logger := slog.With("rid", jobs.GetRID(), "video_path", jobs.GetVideoPath(), "transcript_path", jobs.GetTranscriptPath())
+				// queue 에서 기다리는 동안 취소된 작업은 자막을 만들지 않음
+				if !g.processed.MarkProcessed(jobs, process.GENERATE_SUBTITLE_START) {
+					logger.Info("skip generate subtitle, job cancelled or finished", "step", process.GENERATE_SUBTITLE_START)
+					return
+				}
+				logger.Info("start generate subtitle goroutine", "step", process.GENERATE_SUBTITLE_START, "queue_depth", queueDepth, "running", pool.Running())
+
+				if err := g.generate(jobCtx, jobs); err != nil {
+					if g.processed.Interrupted(ctx, jobs, process.REQUEST_GROQ_API_END) {
+						return
+					}
+					logger.Error("failed generate subtitle", "err", err.Error(), "step", process.GENERATE_SUBTITLE_FAILED)
+					g.failure.Fail(jobs, process.GENERATE_SUBTITLE_FAILED, err)
+					return
+				}
+
+				if !g.processed.MarkProcessed(jobs, process.GENERATE_SUBTITLE_COMPLETE) {
+					logger.Info("job cancelled during generate subtitle", "step", process.GENERATE_SUBTITLE_COMPLETE)
+					return
+				}
+				g.processed.MarkProcessed(jobs, process.ALL_PROCESS_COMPLETE)
+				logger.Info("end generate subtitle goroutine", "step", process.ALL_PROCESS_COMPLETE)
+			})
+		}
+	}
+
+	slog.Debug("waiting for all subtitle goroutines to finish")
+	pool.Wait()
+	slog.Debug("all subtitle goroutines completed")
+
+	return nil
+}
+
+// generate STT_SUBTITLE_TIMEOUT 안에 설정된 형식의 자막 파일을 모두 기록
+func (g *Generator) generate(ctx context.Context, jobs *job.Job) error {
+	ctx, cancel := pipeline.WithTimeout(ctx, g.cfg.Timeout)
+	defer cancel()
+
+	transcript, err := stt.LoadTranscript(jobs.GetTranscriptPath())
+	if err != nil {
+		return err
+	}
+
+	doc := NewDocument(jobs.GetRID(), transcript)
+	if g.cfg.Resegment {
+		doc.Cues = Resegment(transcript, g.rules)
+	}
+	for i := range doc.Cues {
+		doc.Cues[i].Settings = g.cfg.VTTCueSettings
+	}
+
+	writers, err := g.profileWriters(jobs.GetProfile())
+	if err != nil {
+		return err
+	}
+
+	outputDir := g.outputDir(jobs)
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("failed creating output dir: %w", err)
+	}
+
+	for format, writer := range writers {
+		if err := g.writeFile(ctx, jobs, outputDir, format, writer, doc); err != nil {
+			return failure.Timeout(ctx, g.cfg.Timeout, err)
+		}
+	}
+	return nil
+}
+
+// outputDir 프로필 output_dir (없으면 STT_RESULT_DIR/<프로필 이름>) 아래에 watch 디렉토리의 하위 폴더 구조를 유지
+// 다른 watch 디렉토리의 같은 이름 영상이 서로의 결과물을 덮어쓰지 않도록 함, default 프로필은 STT_RESULT_DIR 바로 아래
+func (g *Generator) outputDir(jobs *job.Job) string {
+	profile := jobs.GetProfile()
+
+	outputDir := g.cfg.OutputDir
+	switch {
+	case profile.OutputDir != "":
+		outputDir = profile.OutputDir
+	case profile.Name != "" && profile.Name != config.DEFAULT_PROFILE:
+		outputDir = filepath.Join(outputDir, profile.Name)
+	}
+	return filepath.Join(outputDir, jobs.RelativeDir())
+}
+
+// profileWriters 프로필에 출력 형식이 지정되어 있으면 해당 형식, 없으면 STT_OUTPUT_FORMATS
+func (g *Generator) profileWriters(profile config.Profile) (map[string]Writer, error) {
+	if len(profile.OutputFormats) == 0 {
+		return g.writers, nil
+	}
+
+	writers := make(map[string]Writer, len(profile.OutputFormats))
+	for _, format := range profile.OutputFormats {
+		writer, err := Lookup(format)
+		if err != nil {
+			return nil, err
+		}
+		writers[format] = writer
+	}
+	return writers, nil
+}
+
+func (g *Generator) writeFile(ctx context.Context, jobs *job.Job, outputDir, format string, writer Writer, doc *Document) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	outputPath := utils.GetOutputPath(outputDir, jobs.GetFilename(), writer.Ext())
+	logger := slog.With("rid", jobs.GetRID(), "video_path", jobs.GetVideoPath(), "output_path", outputPath, "output_type", format)
+
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("failed creating output file: %w", err)
+	}
+	defer file.Close()
+
+	if err := writer.Write(ctxWriter{ctx: ctx, w: file}, doc); err != nil {
+		return fmt.Errorf("failed writing %s output file: %w", format, err)
+	}
+	jobs.AddArtifact(format, outputPath)
+
+	logger.Info("generate output file", "step", process.GENERATE_SUBTITLE_COMPLETE)
+	return nil
+}
+
+// ctxWriter ctx 가 종료되면 이후 쓰기를 중단
+type ctxWriter struct {
+	ctx context.Context
+	w   io.Writer
+}
+
+func (w ctxWriter) Write(p []byte) (int, error) {
+	if err := w.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return w.w.Write(p)
+}
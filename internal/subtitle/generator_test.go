@@ -0,0 +1,99 @@
+package subtitle
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+	"video-ai-stt/config"
+	"video-ai-stt/internal/failure"
+	"video-ai-stt/internal/job"
+	"video-ai-stt/internal/process"
+	"video-ai-stt/internal/stt"
+)
+
+func newTestGenerator(t *testing.T) (*Generator, *process.ProcessedManager) {
+	t.Helper()
+
+	store, err := process.NewStore(config.Store{Type: process.STORE_TYPE_MEMORY})
+	if err != nil {
+		t.Fatal(err)
+	}
+	manager, err := process.NewProcessedManager(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.Subtitle{
+		Concurrency:   1,
+		Timeout:       time.Minute,
+		OutputDir:     filepath.Join(t.TempDir(), "output"),
+		OutputFormats: []string{"srt"},
+	}
+	generator, err := NewGenerator(cfg, manager, failure.NewRecorder(config.Failure{Dir: filepath.Join(t.TempDir(), "failed")}, manager))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return generator, manager
+}
+
+func transcribedJob(t *testing.T, manager *process.ProcessedManager) *job.Job {
+	t.Helper()
+
+	transcriptPath := filepath.Join(t.TempDir(), "a.json")
+	transcript := &stt.Transcript{Segments: []stt.Segment{{Start: 0, End: 1, Text: "hello"}}}
+	if err := stt.SaveTranscript(transcriptPath, transcript); err != nil {
+		t.Fatal(err)
+	}
+
+	jobs := job.NewJob("/uploads/a.mp4", "a.mp4")
+	jobs.SetTranscriptPath(transcriptPath)
+	manager.MarkProcessed(jobs, process.REQUEST_GROQ_API_END)
+	return jobs
+}
+
+func runGenerator(t *testing.T, g *Generator, jobs *job.Job) {
+	t.Helper()
+
+	transcriptCh := make(chan *job.Job, 1)
+	transcriptCh <- jobs
+	close(transcriptCh)
+	if err := g.Process(context.Background(), transcriptCh); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGeneratorWritesSubtitle(t *testing.T) {
+	g, manager := newTestGenerator(t)
+	jobs := transcribedJob(t, manager)
+
+	runGenerator(t, g, jobs)
+
+	record, _ := manager.Load(jobs.GetVideoPath())
+	if record.Step != process.ALL_PROCESS_COMPLETE {
+		t.Errorf("step = %d, want complete", record.Step)
+	}
+	if _, err := os.Stat(filepath.Join(g.cfg.OutputDir, "a.srt")); err != nil {
+		t.Errorf("subtitle not written: %v", err)
+	}
+}
+
+// queue 에서 기다리는 동안 취소된 작업은 결과물을 만들지 않고 취소 상태를 유지
+func TestGeneratorSkipsCancelledJob(t *testing.T) {
+	g, manager := newTestGenerator(t)
+	jobs := transcribedJob(t, manager)
+	if _, err := manager.Cancel(jobs.GetRID()); err != nil {
+		t.Fatal(err)
+	}
+
+	runGenerator(t, g, jobs)
+
+	record, _ := manager.Load(jobs.GetVideoPath())
+	if record.Step != process.JOB_CANCELLED {
+		t.Errorf("step = %d, want cancelled", record.Step)
+	}
+	if _, err := os.Stat(g.cfg.OutputDir); !os.IsNotExist(err) {
+		t.Errorf("output dir created for cancelled job: %v", err)
+	}
+}
@@ -0,0 +1,34 @@
+package subtitle
+
+import (
+	"encoding/json"
+	"io"
+	"video-ai-stt/internal/stt"
+)
+
+func init() {
+	Register("json", jsonWriter{})
+}
+
+// jsonWriter 자막 편집기에서 사용하는 transcript + cue
+type jsonWriter struct{}
+
+type jsonDocument struct {
+	RID string `json:"rid"`
+	*stt.Transcript
+	Cues []Cue `json:"cues"`
+}
+
+func (jsonWriter) Ext() string {
+	return ".json"
+}
+
+func (jsonWriter) Write(w io.Writer, doc *Document) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ") // 들여쓰기를 위해 설정
+	return encoder.Encode(jsonDocument{
+		RID:        doc.RID,
+		Transcript: doc.Transcript,
+		Cues:       doc.Cues,
+	})
+}
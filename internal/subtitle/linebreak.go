@@ -0,0 +1,74 @@
+package subtitle
+
+import (
+	"math"
+	"strings"
+	"video-ai-stt/internal/hangul"
+)
+
+// piece 줄바꿈 단위, 한 줄에 들어가지 않는 어절은 한글/비한글 경계에서 나뉘어 여러 piece 가 됨
+type piece struct {
+	text  string
+	space bool // 앞 piece 와 띄어 쓰는지 (false 면 같은 어절)
+}
+
+// breakLines maxLines 줄 이내에서 줄 폭이 가장 고르게 되는 어절 경계에서 줄바꿈, 구두점 뒤를 선호
+func breakLines(words []string, maxWidth, maxLines int) []string {
+	var pieces []piece
+	for _, word := range words {
+		for i, run := range hangul.SplitRuns(word, maxWidth) {
+			pieces = append(pieces, piece{text: run, space: i == 0 && len(pieces) > 0})
+		}
+	}
+
+	lines := breakPieces(pieces, maxWidth, maxLines)
+	for i := range lines {
+		lines[i] = hangul.Normalize(lines[i])
+	}
+	return lines
+}
+
+func breakPieces(pieces []piece, maxWidth, maxLines int) []string {
+	text := joinPieces(pieces)
+	if maxLines < 2 || maxWidth <= 0 || textWidth(text) <= maxWidth || len(pieces) < 2 {
+		return []string{text}
+	}
+
+	best, bestCost := 0, math.MaxFloat64
+	for i := 1; i < len(pieces); i++ {
+		first := textWidth(joinPieces(pieces[:i]))
+		second := textWidth(joinPieces(pieces[i:]))
+
+		cost := math.Abs(float64(first - second))
+		if first > maxWidth || second > maxWidth {
+			cost += 1000
+		}
+		if !pieces[i].space {
+			// 어절 중간은 가능한 피함
+			cost += float64(maxWidth)
+		} else if prev := pieces[i-1].text; isSentenceEnd(prev) || isClauseEnd(prev) {
+			cost -= float64(maxWidth) / 4
+		}
+
+		if cost < bestCost {
+			best, bestCost = i, cost
+		}
+	}
+
+	rest := append([]piece{}, pieces[best:]...)
+	rest[0].space = false
+
+	lines := []string{joinPieces(pieces[:best])}
+	return append(lines, breakPieces(rest, maxWidth, maxLines-1)...)
+}
+
+func joinPieces(pieces []piece) string {
+	var builder strings.Builder
+	for _, p := range pieces {
+		if p.space {
+			builder.WriteByte(' ')
+		}
+		builder.WriteString(p.text)
+	}
+	return builder.String()
+}
@@ -0,0 +1,32 @@
+package subtitle
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBreakLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		maxWidth int
+		maxLines int
+		want     []string
+	}{
+		{name: "한 줄에 들어감", text: "짧은 문장", maxWidth: 42, maxLines: 2, want: []string{"짧은 문장"}},
+		{name: "고르게 나눔", text: "오늘은 제품 발표 날입니다 이 제품은 인공지능 기반", maxWidth: 30, maxLines: 2, want: []string{"오늘은 제품 발표 날입니다", "이 제품은 인공지능 기반"}},
+		{name: "구두점 뒤 선호", text: "네, 그렇습니다 바로 그 부분입니다", maxWidth: 20, maxLines: 2, want: []string{"네, 그렇습니다", "바로 그 부분입니다"}},
+		{name: "한 줄 제한", text: "줄바꿈 없이 한 줄로 표시되는 긴 문장", maxWidth: 10, maxLines: 1, want: []string{"줄바꿈 없이 한 줄로 표시되는 긴 문장"}},
+		{name: "긴 어절은 한글/영문 경계에서 나눔", text: "Kubernetes클러스터에서", maxWidth: 14, maxLines: 2, want: []string{"Kubernetes", "클러스터에서"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := breakLines(strings.Fields(tt.text), tt.maxWidth, tt.maxLines)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("breakLines(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,257 @@
+package subtitle
+
+import (
+	"math"
+	"strings"
+	"unicode/utf8"
+	"video-ai-stt/config"
+	"video-ai-stt/internal/hangul"
+	"video-ai-stt/internal/stt"
+)
+
+const (
+	// longPause 이 이상 말이 끊기면 항상 cue 를 나눔
+	longPause = 1.0
+	// shortPause 구두점이 없을 때 대신 사용하는 끊어 읽기 지점
+	shortPause = 0.3
+)
+
+// Rules 화면에 표시되는 cue 의 가독성 기준
+type Rules struct {
+	// MaxCharsPerLine 한 줄의 최대 표시 폭 (한글 2칸, 영문/숫자 1칸)
+	MaxCharsPerLine int
+	MaxLines        int
+	MaxCPS          float64
+	MinDuration     float64
+	MaxDuration     float64
+	MinGap          float64
+}
+
+func NewRules(cfg config.Subtitle) Rules {
+	rules := Rules{
+		MaxCharsPerLine: cfg.MaxCharsPerLine,
+		MaxLines:        cfg.MaxLines,
+		MaxCPS:          cfg.MaxCPS,
+		MinDuration:     cfg.MinCueDuration.Seconds(),
+		MaxDuration:     cfg.MaxCueDuration.Seconds(),
+		MinGap:          cfg.MinCueGap.Seconds(),
+	}
+	if rules.MaxLines < 1 {
+		rules.MaxLines = 1
+	}
+	return rules
+}
+
+// Resegment 단어 타임스탬프를 기준으로 cue 를 다시 나눔
+// 단어 정보가 없는 공급자는 세그먼트 텍스트를 글자 수 비율로 나눈 단어 시간으로 대신함
+func Resegment(transcript *stt.Transcript, rules Rules) []Cue {
+	words := normalizeWords(transcript.Words)
+	if len(words) == 0 {
+		words = estimateWords(transcript.Segments)
+	}
+	if len(words) == 0 {
+		return nil
+	}
+
+	var groups [][]stt.Word
+	current := []stt.Word{words[0]}
+	for _, word := range words[1:] {
+		prev := current[len(current)-1]
+
+		if word.Start-prev.End >= longPause || (isSentenceEnd(prev.Word) && prev.End-current[0].Start >= rules.MinDuration) {
+			groups = append(groups, current)
+			current = []stt.Word{word}
+			continue
+		}
+
+		candidate := append(current, word)
+		if fits(candidate, rules) {
+			current = candidate
+			continue
+		}
+
+		head, tail := splitAtBestBreak(current)
+		groups = append(groups, head)
+		current = append(tail, word)
+		for !fits(current, rules) && len(current) > 1 {
+			head, tail = splitAtBestBreak(current[:len(current)-1])
+			groups = append(groups, head)
+			current = append(tail, current[len(current)-1])
+		}
+	}
+	groups = append(groups, current)
+
+	cues := make([]Cue, 0, len(groups))
+	for _, group := range groups {
+		cues = append(cues, Cue{
+			Index: len(cues) + 1,
+			Start: group[0].Start,
+			End:   group[len(group)-1].End,
+			Lines: breakLines(wordTexts(group), rules.MaxCharsPerLine, rules.MaxLines),
+		})
+	}
+
+	adjustTiming(cues, rules)
+	return cues
+}
+
+// fits 한 cue 에 담을 수 있는지, 최대 표시 시간, 줄 수와 줄 폭, 초당 글자 수 기준
+// 초당 글자 수는 최소 표시 시간까지 늘려 보여줄 수 있으므로 MinDuration 보다 짧은 cue 는 MinDuration 으로 계산
+func fits(words []stt.Word, rules Rules) bool {
+	duration := words[len(words)-1].End - words[0].Start
+	if rules.MaxDuration > 0 && duration > rules.MaxDuration {
+		return false
+	}
+
+	lines := breakLines(wordTexts(words), rules.MaxCharsPerLine, rules.MaxLines)
+	if rules.MaxCharsPerLine > 0 {
+		for _, line := range lines {
+			if textWidth(line) > rules.MaxCharsPerLine {
+				return false
+			}
+		}
+	}
+
+	if rules.MaxCPS > 0 {
+		display := math.Max(duration, rules.MinDuration)
+		if display > 0 && float64(charCount(strings.Join(lines, "")))/display > rules.MaxCPS {
+			return false
+		}
+	}
+	return true
+}
+
+// splitAtBestBreak 문장 끝 > 절 구분 구두점 > 말 끊김 순으로 선호하는 지점에서 나눔
+// 앞부분이 너무 짧아지지 않도록 후반부에서만 찾고, 없으면 마지막 단어 앞에서 나눔
+func splitAtBestBreak(words []stt.Word) ([]stt.Word, []stt.Word) {
+	if len(words) < 2 {
+		return words, nil
+	}
+
+	best, bestScore := len(words)-1, 0
+	for i := len(words) / 2; i < len(words)-1; i++ {
+		score := 0
+		switch {
+		case isSentenceEnd(words[i].Word):
+			score = 3
+		case isClauseEnd(words[i].Word):
+			score = 2
+		case words[i+1].Start-words[i].End >= shortPause:
+			score = 1
+		}
+		if score >= bestScore && score > 0 {
+			best, bestScore = i, score
+		}
+	}
+
+	head := append([]stt.Word{}, words[:best+1]...)
+	tail := append([]stt.Word{}, words[best+1:]...)
+	return head, tail
+}
+
+// adjustTiming 최소 간격 확보 후, 최소 표시 시간과 초당 글자 수를 만족하도록 다음 cue 전까지 종료 시간을 늘림
+// 다음 cue 가 최소 표시 시간 안에 시작하면 다음 cue 의 시작을 뒤로 밀어 겹치거나 뒤집히지 않도록 함
+func adjustTiming(cues []Cue, rules Rules) {
+	minimal := rules.MinDuration
+	if rules.MaxDuration > 0 {
+		minimal = math.Min(minimal, rules.MaxDuration)
+	}
+
+	for i := range cues {
+		cues[i].End = math.Max(cues[i].End, cues[i].Start)
+
+		limit := math.Inf(1)
+		if i < len(cues)-1 {
+			limit = math.Max(cues[i+1].Start-rules.MinGap, cues[i].Start+minimal)
+		}
+
+		if cues[i].End > limit {
+			cues[i].End = limit
+		}
+
+		required := rules.MinDuration
+		if rules.MaxCPS > 0 {
+			required = math.Max(required, float64(charCount(strings.Join(cues[i].Lines, "")))/rules.MaxCPS)
+		}
+		if rules.MaxDuration > 0 {
+			required = math.Min(required, rules.MaxDuration)
+		}
+
+		if end := cues[i].Start + required; cues[i].End < end {
+			cues[i].End = math.Max(cues[i].End, math.Min(end, limit))
+		}
+
+		if i < len(cues)-1 && cues[i+1].Start < cues[i].End+rules.MinGap {
+			cues[i+1].Start = cues[i].End + rules.MinGap
+		}
+	}
+}
+
+// normalizeWords 띄어 쓰여진 조사와 문장 부호를 앞 단어에 붙여 cue 나 줄 경계에서 떨어지지 않도록 함
+func normalizeWords(words []stt.Word) []stt.Word {
+	normalized := make([]stt.Word, 0, len(words))
+	for _, word := range words {
+		word.Word = strings.TrimSpace(word.Word)
+		if word.Word == "" {
+			continue
+		}
+
+		if n := len(normalized); n > 0 && hangul.IsAttachable(normalized[n-1].Word, word.Word) {
+			normalized[n-1].Word += word.Word
+			normalized[n-1].End = word.End
+			continue
+		}
+		normalized = append(normalized, word)
+	}
+	return normalized
+}
+
+// estimateWords 세그먼트 시간을 글자 수 비율로 단어에 나눠줌
+func estimateWords(segments []stt.Segment) []stt.Word {
+	var words []stt.Word
+	for _, segment := range segments {
+		fields := hangul.Eojeols(segment.Text)
+		total := 0
+		for _, field := range fields {
+			total += textWidth(field)
+		}
+		if total == 0 {
+			continue
+		}
+
+		perChar := (segment.End - segment.Start) / float64(total)
+		start := segment.Start
+		for _, field := range fields {
+			end := start + perChar*float64(textWidth(field))
+			words = append(words, stt.Word{Word: field, Start: start, End: end})
+			start = end
+		}
+	}
+	return words
+}
+
+func wordTexts(words []stt.Word) []string {
+	texts := make([]string, 0, len(words))
+	for _, word := range words {
+		texts = append(texts, word.Word)
+	}
+	return texts
+}
+
+// textWidth 줄 길이는 글자 수가 아닌 표시 폭(한글 2칸)으로 계산
+func textWidth(text string) int {
+	return hangul.Width(text)
+}
+
+// charCount 초당 글자 수 계산용, 공백 제외 글자 수
+func charCount(text string) int {
+	return utf8.RuneCountInString(strings.ReplaceAll(text, " ", ""))
+}
+
+func isSentenceEnd(word string) bool {
+	return strings.HasSuffix(word, ".") || strings.HasSuffix(word, "?") || strings.HasSuffix(word, "!") || strings.HasSuffix(word, "…")
+}
+
+func isClauseEnd(word string) bool {
+	return strings.HasSuffix(word, ",") || strings.HasSuffix(word, ";") || strings.HasSuffix(word, ":")
+}
@@ -0,0 +1,158 @@
+package subtitle
+
+import (
+	"strings"
+	"testing"
+	"video-ai-stt/internal/stt"
+)
+
+func defaultRules() Rules {
+	return Rules{
+		MaxCharsPerLine: 42,
+		MaxLines:        2,
+		MaxCPS:          17,
+		MinDuration:     1,
+		MaxDuration:     7,
+		MinGap:          0.08,
+	}
+}
+
+// timedWords 어절마다 글자 수에 비례한 시간을 주어 perChar 초/글자 속도로 말하는 단어 목록
+func timedWords(text string, start, perChar float64) []stt.Word {
+	var words []stt.Word
+	for _, field := range strings.Fields(text) {
+		end := start + perChar*float64(charCount(field))
+		words = append(words, stt.Word{Word: field, Start: start, End: end})
+		start = end + 0.05
+	}
+	return words
+}
+
+func checkCues(t *testing.T, cues []Cue, rules Rules) {
+	t.Helper()
+
+	for _, cue := range cues {
+		if len(cue.Lines) > rules.MaxLines {
+			t.Errorf("cue %d has %d lines, max %d: %q", cue.Index, len(cue.Lines), rules.MaxLines, cue.Lines)
+		}
+		for _, line := range cue.Lines {
+			if width := textWidth(line); width > rules.MaxCharsPerLine {
+				t.Errorf("cue %d line width %d > %d: %q", cue.Index, width, rules.MaxCharsPerLine, line)
+			}
+		}
+		if duration := cue.End - cue.Start; duration > rules.MaxDuration+1e-9 {
+			t.Errorf("cue %d duration %.2f > %.2f", cue.Index, duration, rules.MaxDuration)
+		}
+	}
+	for i := 1; i < len(cues); i++ {
+		if cues[i].Start < cues[i-1].End {
+			t.Errorf("cue %d overlaps previous cue", cues[i].Index)
+		}
+	}
+}
+
+func TestResegmentLineWidth(t *testing.T) {
+	rules := defaultRules()
+	text := "안녕하세요 오늘은 제품 발표 날입니다 이 제품은 인공지능 기반의 자막 생성 서비스로 영상을 올리면 자동으로 자막을 만들어 줍니다"
+	cues := Resegment(&stt.Transcript{Words: timedWords(text, 0, 0.08)}, rules)
+	if len(cues) < 2 {
+		t.Fatalf("expected multiple cues, got %d", len(cues))
+	}
+	checkCues(t, cues, rules)
+
+	var joined []string
+	for _, cue := range cues {
+		joined = append(joined, cue.Lines...)
+	}
+	if got := strings.Join(joined, " "); got != text {
+		t.Errorf("text changed\\n got: %q\\nwant: %q", got, text)
+	}
+}
+
+func TestResegmentCPS(t *testing.T) {
+	rules := defaultRules()
+	// 초당 약 12.5 글자로 말하는 긴 문장, 한 cue 에 모두 담으면 표시 시간이 MaxDuration 안이어도 cps 를 넘지 않아야 함
+	text := "this is a fast spoken english sentence that keeps going without any pause at all for a while"
+	cues := Resegment(&stt.Transcript{Words: timedWords(text, 0, 0.08)}, rules)
+	checkCues(t, cues, rules)
+
+	for _, cue := range cues {
+		chars := float64(charCount(strings.Join(cue.Lines, "")))
+		if chars/(cue.End-cue.Start) > rules.MaxCPS {
+			t.Errorf("cue %d cps %.1f > %.1f: %q", cue.Index, chars/(cue.End-cue.Start), rules.MaxCPS, cue.Lines)
+		}
+	}
+}
+
+func TestFits(t *testing.T) {
+	rules := defaultRules()
+	tests := []struct {
+		name  string
+		words []stt.Word
+		want  bool
+	}{
+		{name: "짧은 cue", words: timedWords("안녕하세요 여러분", 0, 0.1), want: true},
+		{name: "줄 폭 초과", words: timedWords("날입니다 이 제품은 인공지능 기반의 자막 생성 서비스로 영상을 올리면 자동으로 자막을 만들어", 0, 0.05), want: false},
+		{name: "최대 표시 시간 초과", words: timedWords("천천히 말하는 문장", 0, 1), want: false},
+		{name: "초당 글자 수 초과", words: timedWords("abcdefghij klmnopqrst uvwxyz", 0, 0.01), want: false},
+		{name: "최소 표시 시간으로 계산", words: timedWords("짧게 말함", 0, 0.05), want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fits(tt.words, rules); got != tt.want {
+				t.Errorf("fits(%q) = %v, want %v", wordTexts(tt.words), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResegmentLongPause(t *testing.T) {
+	words := append(timedWords("첫 번째", 0, 0.1), timedWords("두 번째", 5, 0.1)...)
+	cues := Resegment(&stt.Transcript{Words: words}, defaultRules())
+	if len(cues) != 2 {
+		t.Fatalf("expected 2 cues split by long pause, got %d", len(cues))
+	}
+}
+
+func TestResegmentEstimatedWords(t *testing.T) {
+	transcript := &stt.Transcript{Segments: []stt.Segment{
+		{Start: 0, End: 3, Text: "단어 정보가 없는 공급자 에서 받은 결과."},
+	}}
+	cues := Resegment(transcript, defaultRules())
+	if len(cues) != 1 {
+		t.Fatalf("expected 1 cue, got %d", len(cues))
+	}
+	if got := strings.Join(cues[0].Lines, " "); got != "단어 정보가 없는 공급자에서 받은 결과." {
+		t.Errorf("lines = %q", cues[0].Lines)
+	}
+	if cues[0].Start != 0 || cues[0].End != 3 {
+		t.Errorf("cue time = %.2f-%.2f, want 0-3", cues[0].Start, cues[0].End)
+	}
+}
+
+func TestAdjustTimingCloseStart(t *testing.T) {
+	rules := defaultRules()
+	cues := []Cue{
+		{Index: 1, Start: 10, End: 10.5, Lines: []string{"짧은 말"}},
+		{Index: 2, Start: 10.05, End: 10.4, Lines: []string{"바로 이어진 말"}},
+		{Index: 3, Start: 20, End: 21, Lines: []string{"떨어진 말"}},
+	}
+	adjustTiming(cues, rules)
+
+	if got := cues[0].End - cues[0].Start; got < rules.MinDuration-1e-9 {
+		t.Errorf("cue 1 duration %.2f < %.2f", got, rules.MinDuration)
+	}
+	if cues[1].Start < cues[0].End+rules.MinGap-1e-9 {
+		t.Errorf("cue 2 start %.2f, want >= %.2f", cues[1].Start, cues[0].End+rules.MinGap)
+	}
+	for _, cue := range cues {
+		if cue.End < cue.Start {
+			t.Errorf("cue %d end %.2f before start %.2f", cue.Index, cue.End, cue.Start)
+		}
+	}
+	if cues[2].Start != 20 || cues[2].End != 21 {
+		t.Errorf("cue 3 time = %.2f-%.2f, want 20-21", cues[2].Start, cues[2].End)
+	}
+	checkCues(t, cues, rules)
+}
@@ -0,0 +1,28 @@
+package subtitle
+
+import (
+	"fmt"
+	"io"
+)
+
+func init() {
+	Register("srt", srtWriter{})
+}
+
+// srtWriter SubRip
+type srtWriter struct{}
+
+func (srtWriter) Ext() string {
+	return ".srt"
+}
+
+func (srtWriter) Write(w io.Writer, doc *Document) error {
+	for _, cue := range doc.Cues {
+		start := formatTime(cue.Start, ",")
+		end := formatTime(cue.End, ",")
+		if _, err := fmt.Fprintf(w, "%d\n%s --> %s\n%s\n\n", cue.Index, start, end, cue.Text()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
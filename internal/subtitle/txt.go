@@ -0,0 +1,27 @@
+package subtitle
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+func init() {
+	Register("txt", txtWriter{})
+}
+
+// txtWriter 타임스탬프 없는 본문, cue 당 한 줄
+type txtWriter struct{}
+
+func (txtWriter) Ext() string {
+	return ".txt"
+}
+
+func (txtWriter) Write(w io.Writer, doc *Document) error {
+	for _, cue := range doc.Cues {
+		if _, err := fmt.Fprintln(w, strings.Join(cue.Lines, " ")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
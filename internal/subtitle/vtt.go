@@ -0,0 +1,44 @@
+package subtitle
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+func init() {
+	Register("vtt", vttWriter{})
+}
+
+// vttEscaper cue 본문에서 태그와 문자 참조로 해석되는 문자, "-->" 도 함께 막음
+var vttEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// vttWriter WebVTT, NOTE 블록에 rid 와 모델명을 기록
+type vttWriter struct{}
+
+func (vttWriter) Ext() string {
+	return ".vtt"
+}
+
+func (vttWriter) Write(w io.Writer, doc *Document) error {
+	var vtt strings.Builder
+	vtt.WriteString("WEBVTT\n\n")
+
+	vtt.WriteString("NOTE\n")
+	vtt.WriteString(fmt.Sprintf("rid: %s\n", doc.RID))
+	vtt.WriteString(fmt.Sprintf("model: %s\n\n", doc.Transcript.Model))
+
+	for _, cue := range doc.Cues {
+		start := formatTime(cue.Start, ".")
+		end := formatTime(cue.End, ".")
+
+		settings := ""
+		if cue.Settings != "" {
+			settings = " " + cue.Settings
+		}
+		vtt.WriteString(fmt.Sprintf("%d\n%s --> %s%s\n%s\n\n", cue.Index, start, end, settings, vttEscaper.Replace(cue.Text())))
+	}
+
+	_, err := io.WriteString(w, vtt.String())
+	return err
+}
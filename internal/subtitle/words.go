@@ -0,0 +1,71 @@
+package subtitle
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"video-ai-stt/internal/stt"
+)
+
+func init() {
+	Register("words.json", wordsJSONWriter{})
+	Register("words.csv", wordsCSVWriter{})
+}
+
+// wordsJSONWriter 자막 편집기 재타이밍용 단어 단위 타임스탬프
+type wordsJSONWriter struct{}
+
+type wordsDocument struct {
+	RID   string     `json:"rid"`
+	Model string     `json:"model"`
+	Words []stt.Word `json:"words"`
+}
+
+func (wordsJSONWriter) Ext() string {
+	return ".words.json"
+}
+
+func (wordsJSONWriter) Write(w io.Writer, doc *Document) error {
+	words := doc.Transcript.Words
+	if words == nil {
+		words = []stt.Word{}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ") // 들여쓰기를 위해 설정
+	return encoder.Encode(wordsDocument{
+		RID:   doc.RID,
+		Model: doc.Transcript.Model,
+		Words: words,
+	})
+}
+
+// wordsCSVWriter word,start,end
+type wordsCSVWriter struct{}
+
+func (wordsCSVWriter) Ext() string {
+	return ".words.csv"
+}
+
+func (wordsCSVWriter) Write(w io.Writer, doc *Document) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"word", "start", "end"}); err != nil {
+		return err
+	}
+
+	for _, word := range doc.Transcript.Words {
+		record := []string{
+			strings.TrimSpace(word.Word),
+			strconv.FormatFloat(word.Start, 'f', 3, 64),
+			strconv.FormatFloat(word.End, 'f', 3, 64),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
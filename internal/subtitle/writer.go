@@ -0,0 +1,54 @@
+package subtitle
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Writer Document 를 하나의 자막 형식으로 기록
+type Writer interface {
+	Ext() string
+	Write(w io.Writer, doc *Document) error
+}
+
+var writers = map[string]Writer{}
+
+// Register 형식 이름으로 writer 등록, 각 형식 파일의 init 에서 호출
+func Register(format string, writer Writer) {
+	writers[strings.ToLower(format)] = writer
+}
+
+func Lookup(format string) (Writer, error) {
+	writer, ok := writers[strings.ToLower(strings.TrimSpace(format))]
+	if !ok {
+		return nil, fmt.Errorf("unsupported output format: %s, supported: %s", format, strings.Join(Formats(), ","))
+	}
+	return writer, nil
+}
+
+func Formats() []string {
+	formats := make([]string, 0, len(writers))
+	for format := range writers {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+// formatTime HH:MM:SS{sep}mmm, 밀리초 단위로 반올림한 정수에서 계산 (2.3 → 00:00:02,300)
+func formatTime(seconds float64, sep string) string {
+	ms := int64(math.Round(seconds * 1000))
+	if ms < 0 {
+		ms = 0
+	}
+
+	hours := ms / 3600000
+	minutes := ms % 3600000 / 60000
+	secs := ms % 60000 / 1000
+	milliseconds := ms % 1000
+
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d", hours, minutes, secs, sep, milliseconds)
+}
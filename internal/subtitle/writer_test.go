@@ -0,0 +1,49 @@
+package subtitle
+
+import (
+	"strings"
+	"testing"
+	"video-ai-stt/internal/stt"
+)
+
+func TestFormatTime(t *testing.T) {
+	tests := []struct {
+		seconds float64
+		sep     string
+		want    string
+	}{
+		{seconds: 0, sep: ",", want: "00:00:00,000"},
+		{seconds: 2.3, sep: ",", want: "00:00:02,300"},
+		{seconds: 0.1 + 0.2, sep: ".", want: "00:00:00.300"},
+		{seconds: 59.9996, sep: ",", want: "00:01:00,000"},
+		{seconds: 3599.999, sep: ".", want: "00:59:59.999"},
+		{seconds: 3723.0456, sep: ",", want: "01:02:03,046"},
+		{seconds: -0.01, sep: ",", want: "00:00:00,000"},
+	}
+
+	for _, tt := range tests {
+		if got := formatTime(tt.seconds, tt.sep); got != tt.want {
+			t.Errorf("formatTime(%v) = %s, want %s", tt.seconds, got, tt.want)
+		}
+	}
+}
+
+func TestVTTEscapesCueText(t *testing.T) {
+	doc := &Document{
+		RID:        "rid-1",
+		Transcript: &stt.Transcript{Model: "whisper"},
+		Cues: []Cue{
+			{Index: 1, Start: 0, End: 1, Lines: []string{"a <b and R&D", "x --> y"}},
+		},
+	}
+
+	var out strings.Builder
+	if err := (vttWriter{}).Write(&out, doc); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "1\n00:00:00.000 --> 00:00:01.000\na &lt;b and R&amp;D\nx --&gt; y\n\n"
+	if !strings.HasSuffix(out.String(), want) {
+		t.Errorf("vtt output = %q, want suffix %q", out.String(), want)
+	}
+}
@@ -0,0 +1,80 @@
+package uploader
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+const MANIFEST_EXT = ".manifest.json"
+
+// Manifest 이어 올리기를 위해 .working 에 함께 저장되는 업로드 진행 상태
+// Chunks 는 기록이 끝난 chunk 의 index → sha256
+type Manifest struct {
+	Source    string         `json:"source"`
+	Size      int64          `json:"size"`
+	ModTime   time.Time      `json:"mod_time"`
+	SHA256    string         `json:"sha256"`
+	ChunkSize int64          `json:"chunk_size"`
+	Chunks    map[int]string `json:"chunks"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+// ChunkCount 파일 크기를 chunk 크기로 나눈 전체 chunk 수
+func (m *Manifest) ChunkCount() int {
+	if m.Size == 0 {
+		return 0
+	}
+	return int((m.Size + m.ChunkSize - 1) / m.ChunkSize)
+}
+
+// ChunkRange index 번째 chunk 의 시작 위치와 길이
+func (m *Manifest) ChunkRange(index int) (int64, int64) {
+	offset := int64(index) * m.ChunkSize
+	return offset, min(m.ChunkSize, m.Size-offset)
+}
+
+// Matches 원본 파일이 manifest 작성 이후 바뀌지 않았는지 확인
+func (m *Manifest) Matches(source string, info os.FileInfo, chunkSize int64) bool {
+	return m.Source == source && m.Size == info.Size() && m.ModTime.Equal(info.ModTime()) && m.ChunkSize == chunkSize
+}
+
+func loadManifest(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed reading manifest: %w", err)
+	}
+
+	manifest := &Manifest{}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("failed unmarshalling manifest: %w", err)
+	}
+	if manifest.Chunks == nil {
+		manifest.Chunks = make(map[int]string)
+	}
+	return manifest, nil
+}
+
+// saveManifest 임시 파일에 기록 후 rename, 중단되어도 이전 manifest 는 유지됨
+func saveManifest(path string, manifest *Manifest) error {
+	manifest.UpdatedAt = time.Now()
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed marshalling manifest: %w", err)
+	}
+
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("failed writing manifest: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed renaming manifest: %w", err)
+	}
+	return nil
+}
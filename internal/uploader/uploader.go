@@ -0,0 +1,250 @@
+package uploader
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"video-ai-stt/config"
+)
+
+const PART_EXT = ".part"
+
+var (
+	ErrDestinationExists = errors.New("destination file already exists")
+	ErrChecksumMismatch  = errors.New("checksum mismatch")
+)
+
+// Uploader 영상을 chunk 단위로 .working 에 기록하고 완료 후 watcher 디렉토리로 옮기는 업로드 클라이언트
+// 중단된 업로드는 manifest 에 기록된 chunk 를 검증한 뒤 남은 chunk 부터 이어서 기록
+type Uploader struct {
+	cfg        config.Uploader
+	watcherDir string
+	workingDir string
+}
+
+func NewUploader(cfg config.Uploader, watcherCfg config.WatcherFiles) *Uploader {
+	return &Uploader{
+		cfg:        cfg,
+		watcherDir: watcherCfg.WatcherDir,
+		workingDir: filepath.Join(watcherCfg.WatcherDir, watcherCfg.IgnoreDir),
+	}
+}
+
+// Upload source 를 업로드하고 watcher 디렉토리 안의 최종 경로 반환
+// expectedSHA256 이 주어지면 원본 파일의 checksum 과 비교
+func (u *Uploader) Upload(ctx context.Context, source, expectedSHA256 string) (string, error) {
+	if u.cfg.ChunkSize <= 0 {
+		return "", fmt.Errorf("invalid chunk size: %d", u.cfg.ChunkSize)
+	}
+
+	source, err := filepath.Abs(source)
+	if err != nil {
+		return "", fmt.Errorf("failed resolving source path: %w", err)
+	}
+
+	info, err := os.Stat(source)
+	if err != nil {
+		return "", fmt.Errorf("failed stat source file: %w", err)
+	}
+	if !info.Mode().IsRegular() {
+		return "", fmt.Errorf("source is not a regular file: %s", source)
+	}
+
+	filename := filepath.Base(source)
+	destPath := filepath.Join(u.watcherDir, filename)
+	if _, err := os.Stat(destPath); err == nil {
+		return "", fmt.Errorf("%w: %s", ErrDestinationExists, destPath)
+	}
+
+	if err := os.MkdirAll(u.workingDir, 0755); err != nil {
+		return "", fmt.Errorf("failed creating working dir: %w", err)
+	}
+
+	staging := stagingName(source)
+	partPath := filepath.Join(u.workingDir, staging+PART_EXT)
+	manifestPath := filepath.Join(u.workingDir, staging+MANIFEST_EXT)
+	logger := slog.With("source_path", source, "part_path", partPath, "dest_path", destPath)
+
+	manifest, err := u.prepare(logger, source, info, manifestPath, partPath)
+	if err != nil {
+		return "", err
+	}
+
+	if manifest.SHA256 == "" {
+		if manifest.SHA256, err = fileSHA256(source); err != nil {
+			return "", fmt.Errorf("failed hashing source file: %w", err)
+		}
+		if err := saveManifest(manifestPath, manifest); err != nil {
+			return "", err
+		}
+	}
+	if expectedSHA256 != "" && !strings.EqualFold(expectedSHA256, manifest.SHA256) {
+		return "", fmt.Errorf("%w: source sha256 %s, expected %s", ErrChecksumMismatch, manifest.SHA256, expectedSHA256)
+	}
+
+	if err := u.writeChunks(ctx, logger, source, partPath, manifestPath, manifest); err != nil {
+		return "", err
+	}
+
+	partSHA256, err := fileSHA256(partPath)
+	if err != nil {
+		return "", fmt.Errorf("failed hashing part file: %w", err)
+	}
+	if partSHA256 != manifest.SHA256 {
+		// 다음 실행에서 처음부터 다시 기록하도록 manifest 제거
+		os.Remove(manifestPath)
+		return "", fmt.Errorf("%w: uploaded sha256 %s, source sha256 %s", ErrChecksumMismatch, partSHA256, manifest.SHA256)
+	}
+
+	if _, err := os.Stat(destPath); err == nil {
+		return "", fmt.Errorf("%w: %s", ErrDestinationExists, destPath)
+	}
+	if err := os.Rename(partPath, destPath); err != nil {
+		return "", fmt.Errorf("failed moving part file to watcher dir: %w", err)
+	}
+	if err := os.Remove(manifestPath); err != nil {
+		logger.Warn("failed removing manifest", "manifest_path", manifestPath, "error", err.Error())
+	}
+
+	logger.Info("upload complete", "size", manifest.Size, "sha256", manifest.SHA256)
+	return destPath, nil
+}
+
+// stagingName .working 에 기록할 part, manifest 파일 이름
+// 다른 디렉토리의 같은 이름 파일과 겹치지 않도록 원본 절대 경로의 sha256 앞부분을 붙임
+func stagingName(source string) string {
+	sum := sha256.Sum256([]byte(filepath.Clean(source)))
+	return hex.EncodeToString(sum[:8]) + "-" + filepath.Base(source)
+}
+
+// prepare 기존 manifest 를 불러와 원본이 그대로인지, 기록된 chunk 가 실제 part 파일과 일치하는지 확인
+func (u *Uploader) prepare(logger *slog.Logger, source string, info os.FileInfo, manifestPath, partPath string) (*Manifest, error) {
+	manifest, err := loadManifest(manifestPath)
+	if err != nil {
+		logger.Warn("ignore broken manifest, restart upload", "manifest_path", manifestPath, "error", err.Error())
+		manifest = nil
+	}
+
+	if manifest != nil && !manifest.Matches(source, info, u.cfg.ChunkSize) {
+		logger.Info("source changed since last upload, restart upload", "manifest_path", manifestPath)
+		manifest = nil
+	}
+
+	if manifest == nil {
+		manifest = &Manifest{
+			Source:    source,
+			Size:      info.Size(),
+			ModTime:   info.ModTime(),
+			ChunkSize: u.cfg.ChunkSize,
+			Chunks:    make(map[int]string),
+		}
+		if err := os.Remove(partPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("failed removing stale part file: %w", err)
+		}
+		return manifest, saveManifest(manifestPath, manifest)
+	}
+
+	received := 0
+	for index, checksum := range manifest.Chunks {
+		offset, length := manifest.ChunkRange(index)
+		actual, err := rangeSHA256(partPath, offset, length)
+		if err != nil || actual != checksum {
+			delete(manifest.Chunks, index)
+			continue
+		}
+		received++
+	}
+
+	logger.Info("resume upload", "received_chunks", received, "chunk_count", manifest.ChunkCount())
+	return manifest, saveManifest(manifestPath, manifest)
+}
+
+func (u *Uploader) writeChunks(ctx context.Context, logger *slog.Logger, source, partPath, manifestPath string, manifest *Manifest) error {
+	src, err := os.Open(source)
+	if err != nil {
+		return fmt.Errorf("failed opening source file: %w", err)
+	}
+	defer src.Close()
+
+	part, err := os.OpenFile(partPath, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return fmt.Errorf("failed opening part file: %w", err)
+	}
+	defer part.Close()
+
+	if err := part.Truncate(manifest.Size); err != nil {
+		return fmt.Errorf("failed truncating part file: %w", err)
+	}
+
+	chunkCount := manifest.ChunkCount()
+	for index := 0; index < chunkCount; index++ {
+		if _, ok := manifest.Chunks[index]; ok {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("upload interrupted: %w", ctx.Err())
+		default:
+		}
+
+		offset, length := manifest.ChunkRange(index)
+		hash := sha256.New()
+		written, err := io.Copy(io.MultiWriter(io.NewOffsetWriter(part, offset), hash), io.NewSectionReader(src, offset, length))
+		if err != nil {
+			return fmt.Errorf("failed writing chunk %d: %w", index, err)
+		}
+		if written != length {
+			return fmt.Errorf("failed writing chunk %d: short write %d/%d", index, written, length)
+		}
+		if err := part.Sync(); err != nil {
+			return fmt.Errorf("failed syncing part file: %w", err)
+		}
+
+		manifest.Chunks[index] = hex.EncodeToString(hash.Sum(nil))
+		if err := saveManifest(manifestPath, manifest); err != nil {
+			return err
+		}
+		logger.Debug("upload chunk", "chunk", index+1, "chunk_count", chunkCount, "offset", offset, "length", length)
+	}
+	return nil
+}
+
+func fileSHA256(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func rangeSHA256(path string, offset, length int64) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	n, err := io.Copy(hash, io.NewSectionReader(file, offset, length))
+	if err != nil {
+		return "", err
+	}
+	if n != length {
+		return "", io.ErrUnexpectedEOF
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,55 @@
+package uploader
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"video-ai-stt/config"
+)
+
+func TestStagingName(t *testing.T) {
+	a := stagingName("/videos/a/lecture.mp4")
+	b := stagingName("/videos/b/lecture.mp4")
+
+	if a == b {
+		t.Fatalf("same staging name for different directories: %s", a)
+	}
+	if !strings.HasSuffix(a, "-lecture.mp4") || !strings.HasSuffix(b, "-lecture.mp4") {
+		t.Errorf("staging name should keep base filename: %s, %s", a, b)
+	}
+	if got := stagingName("/videos/a/../a/lecture.mp4"); got != a {
+		t.Errorf("stagingName(unclean) = %s, want %s", got, a)
+	}
+}
+
+func TestUploadRemovesStagingFiles(t *testing.T) {
+	watcherDir := t.TempDir()
+	source := filepath.Join(t.TempDir(), "lecture.mp4")
+	if err := os.WriteFile(source, []byte("0123456789abcdef"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	u := NewUploader(config.Uploader{ChunkSize: 5}, config.WatcherFiles{WatcherDir: watcherDir, IgnoreDir: ".working"})
+	destPath, err := u.Upload(context.Background(), source, "")
+	if err != nil {
+		t.Fatalf("Upload() error = %v", err)
+	}
+	if destPath != filepath.Join(watcherDir, "lecture.mp4") {
+		t.Errorf("destPath = %s", destPath)
+	}
+
+	data, err := os.ReadFile(destPath)
+	if err != nil || string(data) != "0123456789abcdef" {
+		t.Errorf("uploaded content = %q, err = %v", data, err)
+	}
+
+	entries, err := os.ReadDir(filepath.Join(watcherDir, ".working"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("staging files left: %d", len(entries))
+	}
+}
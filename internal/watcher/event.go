@@ -0,0 +1,105 @@
+package watcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+	"video-ai-stt/internal/job"
+)
+
+var errEventUnsupported = errors.New("event watch mode is not supported on this platform")
+
+// openEventSource 플랫폼별 이벤트 공급자 생성 함수
+var openEventSource = newEventSource
+
+// fileEvent 기록이 끝났거나(close-write) 옮겨진(moved-to) 파일, 또는 새로 생긴 디렉토리
+// overflow 는 커널 이벤트 큐가 넘쳐 이벤트가 유실된 경우로 전체 재탐색 필요
+type fileEvent struct {
+	path     string
+	dir      bool
+	overflow bool
+}
+
+// eventSource 파일 시스템 이벤트 공급자, 새로 생긴 하위 디렉토리도 감시 대상에 추가
+type eventSource interface {
+	Events() <-chan fileEvent
+	Close() error
+}
+
+// watchEvents 이벤트 기반 감시, 놓친 이벤트는 ReconcileInterval 마다 전체 탐색으로 보완
+func (w *Watcher) watchEvents(ctx context.Context, videoCh chan<- *job.Job) error {
+
+	source, err := openEventSource(w.profiles.Dirs(), w.isIgnored)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	// 감시 등록 이전에 올라온 파일
+	w.reconcile(videoCh)
+
+	ticker := time.NewTicker(w.cfg.ReconcileInterval)
+	defer ticker.Stop()
+
+	recheck := time.NewTicker(STABLE_RECHECK_INTERVAL)
+	defer recheck.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Debug("close watcher file goroutine")
+			return nil
+		case event, ok := <-source.Events():
+			if !ok {
+				return fmt.Errorf("watcher event source closed, watcher_dirs: %v", w.profiles.Dirs())
+			}
+			w.handleEvent(event, videoCh)
+		case <-ticker.C:
+			w.reconcile(videoCh)
+		case <-recheck.C:
+			w.recheckPending(videoCh)
+		}
+	}
+}
+
+func (w *Watcher) handleEvent(event fileEvent, videoCh chan<- *job.Job) {
+	switch {
+	case event.overflow:
+		slog.Warn("watcher event queue overflow, reconcile")
+		w.reconcile(videoCh)
+	case event.dir:
+		// 디렉토리가 통째로 옮겨진 경우 안의 파일은 이벤트가 발생하지 않음
+		if err := w.scan(event.path, videoCh, nil); err != nil {
+			slog.Error("failed scan new dir", "dir", event.path, "error", err.Error())
+		}
+	default:
+		info, err := os.Stat(event.path)
+		if err != nil || info.IsDir() {
+			return
+		}
+		if err := w.register(event.path, info, videoCh); errors.Is(err, errQueueFull) {
+			slog.Debug("watcher queue full, wait for recheck", "video_path", event.path, "queue_depth", len(videoCh))
+		}
+	}
+}
+
+// recheckPending 이벤트 이후 안정화나 queue 자리를 기다리던 파일을 다시 확인
+func (w *Watcher) recheckPending(videoCh chan<- *job.Job) {
+	for _, path := range w.stability.pendingPaths() {
+		info, err := os.Stat(path)
+		if err != nil {
+			w.stability.forget(path)
+			continue
+		}
+		if err := w.register(path, info, videoCh); errors.Is(err, errQueueFull) {
+			return
+		}
+	}
+}
+
+func (w *Watcher) reconcile(videoCh chan<- *job.Job) {
+	w.scanAll(videoCh)
+}
@@ -0,0 +1,166 @@
+//go:build linux
+
+package watcher
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	inotifyMask     = unix.IN_CLOSE_WRITE | unix.IN_MOVED_TO | unix.IN_CREATE
+	inotifyEventBuf = unix.SizeofInotifyEvent * 4096
+)
+
+// inotifySource inotify 기반 eventSource, watch descriptor 별 디렉토리 경로를 관리
+type inotifySource struct {
+	fd      int
+	file    *os.File
+	skip    func(string) bool
+	watches map[int]string
+	events  chan fileEvent
+	done    chan struct{}
+}
+
+func newEventSource(roots []string, skip func(string) bool) (eventSource, error) {
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC | unix.IN_NONBLOCK)
+	if err != nil {
+		return nil, fmt.Errorf("failed inotify init: %w", err)
+	}
+
+	s := &inotifySource{
+		fd:      fd,
+		file:    os.NewFile(uintptr(fd), "inotify"),
+		skip:    skip,
+		watches: make(map[int]string),
+		events:  make(chan fileEvent, 256),
+		done:    make(chan struct{}),
+	}
+
+	for _, root := range roots {
+		if err := s.addRecursive(root); err != nil {
+			s.file.Close()
+			return nil, err
+		}
+	}
+
+	go s.read()
+	return s, nil
+}
+
+func (s *inotifySource) Events() <-chan fileEvent {
+	return s.events
+}
+
+func (s *inotifySource) Close() error {
+	close(s.done)
+	return s.file.Close()
+}
+
+// addRecursive dir 와 하위 디렉토리를 감시 대상에 추가 (.working 등 제외 디렉토리는 건너뜀)
+func (s *inotifySource) addRecursive(dir string) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !d.IsDir() {
+			return nil
+		}
+
+		if s.skip(path) {
+			return filepath.SkipDir
+		}
+
+		wd, err := unix.InotifyAddWatch(s.fd, path, inotifyMask)
+		if err != nil {
+			return fmt.Errorf("failed inotify add watch, path: %s: %w", path, err)
+		}
+		s.watches[wd] = path
+		return nil
+	})
+}
+
+func (s *inotifySource) read() {
+	defer close(s.events)
+
+	var buf [inotifyEventBuf]byte
+	for {
+		n, err := s.file.Read(buf[:])
+		if err != nil {
+			if !errors.Is(err, os.ErrClosed) {
+				slog.Error("failed read inotify events", "error", err.Error())
+			}
+			return
+		}
+
+		if !s.handle(buf[:n]) {
+			return
+		}
+	}
+}
+
+// handle read 한 버퍼의 inotify_event 들을 fileEvent 로 변환, 종료된 경우 false
+func (s *inotifySource) handle(buf []byte) bool {
+	for offset := 0; offset+unix.SizeofInotifyEvent <= len(buf); {
+		raw := (*unix.InotifyEvent)(unsafe.Pointer(&buf[offset]))
+		nameStart := offset + unix.SizeofInotifyEvent
+		offset = nameStart + int(raw.Len)
+
+		if raw.Mask&unix.IN_Q_OVERFLOW != 0 {
+			if !s.send(fileEvent{overflow: true}) {
+				return false
+			}
+			continue
+		}
+
+		if raw.Mask&unix.IN_IGNORED != 0 {
+			delete(s.watches, int(raw.Wd))
+			continue
+		}
+
+		dir, ok := s.watches[int(raw.Wd)]
+		if !ok || raw.Len == 0 {
+			continue
+		}
+		path := filepath.Join(dir, strings.TrimRight(string(buf[nameStart:offset]), "\x00"))
+
+		if raw.Mask&unix.IN_ISDIR != 0 {
+			if raw.Mask&(unix.IN_CREATE|unix.IN_MOVED_TO) == 0 || s.skip(path) {
+				continue
+			}
+			if err := s.addRecursive(path); err != nil {
+				slog.Error("failed watch new dir", "dir", path, "error", err.Error())
+			}
+			if !s.send(fileEvent{path: path, dir: true}) {
+				return false
+			}
+			continue
+		}
+
+		// 파일 생성(IN_CREATE)은 기록이 끝나지 않았으므로 close-write, moved-to 만 전달
+		if raw.Mask&(unix.IN_CLOSE_WRITE|unix.IN_MOVED_TO) != 0 {
+			if !s.send(fileEvent{path: path}) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (s *inotifySource) send(event fileEvent) bool {
+	select {
+	case s.events <- event:
+		return true
+	case <-s.done:
+		return false
+	}
+}
@@ -0,0 +1,7 @@
+//go:build !linux
+
+package watcher
+
+func newEventSource(roots []string, skip func(string) bool) (eventSource, error) {
+	return nil, errEventUnsupported
+}
@@ -0,0 +1,72 @@
+//go:build linux
+
+package watcher
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// hasOpenWriter /proc/<pid>/fd 를 확인하여 쓰기 모드로 파일을 열고 있는 프로세스가 있는지 확인
+// 권한이 없는 프로세스는 확인하지 못함
+func hasOpenWriter(path string) (bool, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return false, err
+	}
+
+	procs, err := os.ReadDir("/proc")
+	if err != nil {
+		return false, err
+	}
+
+	for _, proc := range procs {
+		if _, err := strconv.Atoi(proc.Name()); err != nil {
+			continue
+		}
+
+		fdDir := filepath.Join("/proc", proc.Name(), "fd")
+		fds, err := os.ReadDir(fdDir)
+		if err != nil {
+			continue
+		}
+
+		for _, fd := range fds {
+			target, err := os.Readlink(filepath.Join(fdDir, fd.Name()))
+			if err != nil || target != abs {
+				continue
+			}
+			if isWriteMode(filepath.Join("/proc", proc.Name(), "fdinfo", fd.Name())) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// isWriteMode fdinfo 의 flags(8진수)에 O_WRONLY 또는 O_RDWR 가 있는지 확인
+func isWriteMode(fdinfoPath string) bool {
+	file, err := os.Open(fdinfoPath)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		value, ok := strings.CutPrefix(scanner.Text(), "flags:")
+		if !ok {
+			continue
+		}
+		flags, err := strconv.ParseInt(strings.TrimSpace(value), 8, 64)
+		if err != nil {
+			return false
+		}
+		return flags&(syscall.O_WRONLY|syscall.O_RDWR) != 0
+	}
+	return false
+}
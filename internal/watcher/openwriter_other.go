@@ -0,0 +1,8 @@
+//go:build !linux
+
+package watcher
+
+// hasOpenWriter /proc 가 없는 환경에서는 확인하지 않음
+func hasOpenWriter(path string) (bool, error) {
+	return false, nil
+}
@@ -0,0 +1,112 @@
+package watcher
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+	"video-ai-stt/config"
+)
+
+// Profiles watch 디렉토리와 프로필 매핑, WatcherDir 은 default 프로필
+// 디렉토리가 겹치면 파일 경로와 가장 길게 일치하는 디렉토리의 프로필을 사용
+type Profiles struct {
+	profiles []config.Profile
+	roots    []string
+}
+
+func NewProfiles(cfg config.WatcherFiles) *Profiles {
+	profiles := append([]config.Profile{{Name: config.DEFAULT_PROFILE, WatchDir: cfg.WatcherDir}}, cfg.Profiles...)
+
+	roots := make([]string, len(profiles))
+	for i, profile := range profiles {
+		roots[i] = absPath(profile.WatchDir)
+	}
+
+	return &Profiles{
+		profiles: profiles,
+		roots:    roots,
+	}
+}
+
+// Match path 가 속한 watch 디렉토리의 프로필, 어느 디렉토리에도 속하지 않으면 default
+func (p *Profiles) Match(path string) config.Profile {
+	path = absPath(path)
+
+	matched, length := 0, -1
+	for i, root := range p.roots {
+		if isInside(root, path) && len(root) > length {
+			matched, length = i, len(root)
+		}
+	}
+	return p.profiles[matched]
+}
+
+func (p *Profiles) Lookup(name string) (config.Profile, bool) {
+	if name == "" {
+		name = config.DEFAULT_PROFILE
+	}
+	for _, profile := range p.profiles {
+		if profile.Name == name {
+			return profile, true
+		}
+	}
+	return config.Profile{}, false
+}
+
+// Dirs 탐색할 watch 디렉토리, 다른 watch 디렉토리 하위에 있는 디렉토리는 상위 탐색에 포함되므로 제외
+func (p *Profiles) Dirs() []string {
+	order := make([]int, len(p.roots))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return len(p.roots[order[a]]) < len(p.roots[order[b]])
+	})
+
+	var dirs, roots []string
+	for _, i := range order {
+		nested := false
+		for _, root := range roots {
+			if isInside(root, p.roots[i]) {
+				nested = true
+				break
+			}
+		}
+		if nested {
+			continue
+		}
+		roots = append(roots, p.roots[i])
+		dirs = append(dirs, p.profiles[i].WatchDir)
+	}
+	return dirs
+}
+
+// WatchPath path 를 watcher 가 탐색할 때 사용하는 경로(작업 key) 형태로 변환, watch 디렉토리 밖이면 false
+func (p *Profiles) WatchPath(path string) (string, bool) {
+	path = absPath(path)
+	for _, dir := range p.Dirs() {
+		root := absPath(dir)
+		if !isInside(root, path) {
+			continue
+		}
+		rel, _ := filepath.Rel(root, path)
+		return filepath.Join(dir, rel), true
+	}
+	return "", false
+}
+
+func isInside(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return false
+	}
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)))
+}
+
+func absPath(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return filepath.Clean(path)
+	}
+	return abs
+}
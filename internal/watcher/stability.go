@@ -0,0 +1,136 @@
+package watcher
+
+import (
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	"video-ai-stt/config"
+)
+
+// STABLE_RECHECK_INTERVAL event 모드에서 안정화 대기 중인 파일을 다시 확인하는 주기
+const STABLE_RECHECK_INTERVAL = time.Second
+
+type fileState struct {
+	size    int64
+	modTime time.Time
+	since   time.Time
+}
+
+// stabilityGate 기록 중인 파일이 등록되지 않도록 크기/수정 시각이 일정 시간 유지되는지 확인
+type stabilityGate struct {
+	cfg     config.WatcherFiles
+	mu      sync.Mutex
+	pending map[string]fileState
+}
+
+func newStabilityGate(cfg config.WatcherFiles) *stabilityGate {
+	return &stabilityGate{
+		cfg:     cfg,
+		pending: make(map[string]fileState),
+	}
+}
+
+// isStable 처음 본 파일이거나 크기/수정 시각이 바뀐 파일은 대기 목록에 기록하고 false
+// 두 번 이상 같은 상태로 관찰되고 StablePeriod 동안 변경이 없으면 true
+func (g *stabilityGate) isStable(path string, info os.FileInfo) bool {
+	if reason := g.lockReason(path); reason != "" {
+		g.track(path, info, reason)
+		return false
+	}
+
+	if g.cfg.StablePeriod > 0 {
+		now := time.Now()
+
+		g.mu.Lock()
+		state, ok := g.pending[path]
+		g.mu.Unlock()
+
+		if !ok || state.size != info.Size() || !state.modTime.Equal(info.ModTime()) {
+			g.track(path, info, "changed")
+			return false
+		}
+
+		if now.Sub(state.since) < g.cfg.StablePeriod && now.Sub(info.ModTime()) < g.cfg.StablePeriod {
+			return false
+		}
+	}
+
+	if g.cfg.CheckOpenWriters {
+		open, err := hasOpenWriter(path)
+		if err != nil {
+			slog.Warn("failed check open writer", "video_path", path, "error", err.Error())
+		}
+		if open {
+			g.track(path, info, "open_writer")
+			return false
+		}
+	}
+
+	g.forget(path)
+	return true
+}
+
+// lockReason 파일 이름이 .part, .lock 등으로 끝나거나 같은 이름의 잠금 파일이 있으면 아직 기록 중인 것으로 판단
+func (g *stabilityGate) lockReason(path string) string {
+	for _, suffix := range g.cfg.LockSuffixes {
+		if suffix == "" {
+			continue
+		}
+		if strings.HasSuffix(strings.ToLower(path), strings.ToLower(suffix)) {
+			return "lock_suffix" + suffix
+		}
+		if _, err := os.Stat(path + suffix); err == nil {
+			return "lock_file" + suffix
+		}
+	}
+	return ""
+}
+
+func (g *stabilityGate) track(path string, info os.FileInfo, reason string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	state, ok := g.pending[path]
+	if ok && state.size == info.Size() && state.modTime.Equal(info.ModTime()) {
+		return
+	}
+
+	g.pending[path] = fileState{size: info.Size(), modTime: info.ModTime(), since: time.Now()}
+	if !ok {
+		slog.Debug("watcher file not stable yet", "video_path", path, "size", info.Size(), "reason", reason)
+	}
+}
+
+// prune 전체 탐색에서 보이지 않은 (삭제되거나 이름이 바뀐) 파일을 대기 목록에서 제거
+// 탐색 도중 이벤트로 추가된 파일은 남겨둠
+func (g *stabilityGate) prune(seen map[string]bool, scanStart time.Time) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for path, state := range g.pending {
+		if !seen[path] && state.since.Before(scanStart) {
+			delete(g.pending, path)
+			slog.Debug("watcher pending file gone", "video_path", path)
+		}
+	}
+}
+
+func (g *stabilityGate) forget(path string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.pending, path)
+}
+
+// pendingPaths 안정화 대기 중인 파일 목록
+func (g *stabilityGate) pendingPaths() []string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	paths := make([]string, 0, len(g.pending))
+	for path := range g.pending {
+		paths = append(paths, path)
+	}
+	return paths
+}
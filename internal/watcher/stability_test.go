@@ -0,0 +1,97 @@
+package watcher
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+	"video-ai-stt/config"
+)
+
+func writeFile(t *testing.T, path string) os.FileInfo {
+	t.Helper()
+
+	if err := os.WriteFile(path, []byte("data"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return info
+}
+
+func TestLockReason(t *testing.T) {
+	dir := t.TempDir()
+	gate := newStabilityGate(config.WatcherFiles{LockSuffixes: []string{".part", ".lock"}})
+
+	writeFile(t, filepath.Join(dir, "locked.mp4"))
+	writeFile(t, filepath.Join(dir, "locked.mp4.lock"))
+	writeFile(t, filepath.Join(dir, "plain.mp4"))
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: filepath.Join(dir, "video.mp4.part"), want: "lock_suffix.part"},
+		{path: filepath.Join(dir, "VIDEO.MP4.PART"), want: "lock_suffix.part"},
+		{path: filepath.Join(dir, "locked.mp4"), want: "lock_file.lock"},
+		{path: filepath.Join(dir, "plain.mp4"), want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := gate.lockReason(tt.path); got != tt.want {
+			t.Errorf("lockReason(%s) = %q, want %q", filepath.Base(tt.path), got, tt.want)
+		}
+	}
+}
+
+func TestIsStableWaitsForPeriod(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a.mp4")
+	info := writeFile(t, path)
+	gate := newStabilityGate(config.WatcherFiles{StablePeriod: time.Hour})
+
+	if gate.isStable(path, info) {
+		t.Fatal("first observation must not be stable")
+	}
+	if gate.isStable(path, info) {
+		t.Fatal("file stable before StablePeriod")
+	}
+
+	gate.mu.Lock()
+	state := gate.pending[path]
+	state.since = time.Now().Add(-2 * time.Hour)
+	gate.pending[path] = state
+	gate.mu.Unlock()
+
+	if !gate.isStable(path, info) {
+		t.Fatal("file not stable after StablePeriod")
+	}
+	if len(gate.pendingPaths()) != 0 {
+		t.Error("stable file left in pending list")
+	}
+}
+
+func TestPrune(t *testing.T) {
+	dir := t.TempDir()
+	gate := newStabilityGate(config.WatcherFiles{StablePeriod: time.Hour})
+
+	kept := filepath.Join(dir, "kept.mp4")
+	gone := filepath.Join(dir, "gone.mp4")
+	gate.track(kept, writeFile(t, kept), "changed")
+	gate.track(gone, writeFile(t, gone), "changed")
+
+	scanStart := time.Now()
+	late := filepath.Join(dir, "late.mp4")
+	gate.track(late, writeFile(t, late), "changed")
+
+	gate.prune(map[string]bool{kept: true}, scanStart)
+
+	pending := map[string]bool{}
+	for _, path := range gate.pendingPaths() {
+		pending[path] = true
+	}
+	if !pending[kept] || pending[gone] || !pending[late] {
+		t.Errorf("pending after prune = %v", pending)
+	}
+}
@@ -2,6 +2,8 @@ package watcher
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
 	"os"
 	"path/filepath"
@@ -12,21 +14,65 @@ import (
 	"video-ai-stt/internal/process"
 )
 
+const (
+	WATCH_MODE_POLL  = "poll"
+	WATCH_MODE_EVENT = "event"
+)
+
+// errQueueFull videoCh 가 가득 차 등록을 멈춤, 남은 파일은 다음 탐색에서 다시 시도
+var errQueueFull = errors.New("watcher queue full")
+
+// systemFiles OS 가 폴더마다 만드는 파일, 작업 후보에서 제외
+var systemFiles = []string{"Thumbs.db", "desktop.ini"}
+
 type Watcher struct {
 	cfg       config.WatcherFiles
 	processed *process.ProcessedManager
+	stability *stabilityGate
+	profiles  *Profiles
 }
 
 func NewWatcher(cfg config.WatcherFiles, manager *process.ProcessedManager) *Watcher {
 	return &Watcher{
 		cfg:       cfg,
 		processed: manager,
+		stability: newStabilityGate(cfg),
+		profiles:  NewProfiles(cfg),
 	}
 }
 
 func (w *Watcher) Process(ctx context.Context, videoCh chan<- *job.Job) error {
 
-	slog.Debug("watcher start", "watcher_dir", w.cfg.WatcherDir)
+	dirs := w.profiles.Dirs()
+	slog.Debug("watcher start", "watcher_dirs", dirs, "watch_mode", w.cfg.WatchMode)
+
+	for _, dir := range dirs {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed creating watcher dir: %w", err)
+		}
+	}
+
+	switch w.cfg.WatchMode {
+	case WATCH_MODE_POLL:
+		return w.poll(ctx, videoCh)
+	case WATCH_MODE_EVENT:
+		// 감시 등록 실패(max_user_watches 초과 등)나 이벤트 수신이 끊긴 경우에도 감시를 멈추지 않도록 poll 로 전환
+		err := w.watchEvents(ctx, videoCh)
+		switch {
+		case err == nil || ctx.Err() != nil:
+			return nil
+		case errors.Is(err, errEventUnsupported):
+			slog.Warn("event watch mode unsupported, fallback to poll", "watcher_dirs", dirs)
+		default:
+			slog.Error("event watch failed, fallback to poll", "watcher_dirs", dirs, "error", err.Error())
+		}
+		return w.poll(ctx, videoCh)
+	default:
+		return fmt.Errorf("unsupported watch mode: %s", w.cfg.WatchMode)
+	}
+}
+
+func (w *Watcher) poll(ctx context.Context, videoCh chan<- *job.Job) error {
 
 	ticker := time.NewTicker(time.Duration(w.cfg.WatchInterval) * time.Second)
 	defer ticker.Stop()
@@ -34,51 +80,138 @@ func (w *Watcher) Process(ctx context.Context, videoCh chan<- *job.Job) error {
 	for {
 		select {
 		case <-ctx.Done():
-			slog.Debug("close watcher file goroutine", "watcher_dir", w.cfg.WatcherDir)
+			slog.Debug("close watcher file goroutine")
 			return nil
 		case <-ticker.C:
-			err := filepath.Walk(w.cfg.WatcherDir, func(videoPath string, info os.FileInfo, err error) error {
-				if err != nil {
-					return err
-				}
-
-				if info.IsDir() {
-					return nil
-				}
-
-				if strings.Contains(videoPath, w.cfg.IgnoreDir) {
-					return nil
-				}
-
-				filename := info.Name()
-				if !w.checkVideoFile(filename) {
-					return nil
-				}
-
-				alreadyProcess := w.processed.IsProcessed(videoPath, process.WATCHER_FILE_REGISTER)
-				if alreadyProcess {
-					return nil
-				}
-
-				jobs := job.NewJob(videoPath, filename)
-				w.processed.MarkProcessed(videoPath, process.WATCHER_FILE_REGISTER)
-				slog.Info("watcher new file", "rid", jobs.GetRID(), "watcher_dir", w.cfg.WatcherDir, "filename", filename, "video_path", jobs.GetVideoPath(), "step", process.WATCHER_FILE_REGISTER)
-				videoCh <- jobs
-				return nil
-			})
-
-			if err != nil {
-				slog.Error("failed watcher process file", "watcher_dir", w.cfg.WatcherDir, "error", err.Error())
-			}
+			w.scanAll(videoCh)
+		}
+	}
+}
+
+// scanAll 모든 watch 디렉토리 탐색, queue 가 가득 차면 탐색을 멈춤
+// 끝까지 탐색한 경우 사라진 파일을 안정화 대기 목록에서 제거
+func (w *Watcher) scanAll(videoCh chan<- *job.Job) {
+	scanStart := time.Now()
+	seen := map[string]bool{}
+	complete := true
+
+	for _, dir := range w.profiles.Dirs() {
+		err := w.scan(dir, videoCh, seen)
+		if errors.Is(err, errQueueFull) {
+			slog.Warn("watcher queue full, pause registration", "queue_depth", len(videoCh), "queue_size", cap(videoCh))
+			return
+		}
+		if err != nil {
+			slog.Error("failed watcher process file", "watcher_dir", dir, "error", err.Error())
+			complete = false
 		}
 	}
+
+	if complete {
+		w.stability.prune(seen, scanStart)
+	}
+}
+
+// scan dir 하위 전체를 탐색하여 등록되지 않은 영상을 작업으로 등록, seen 에 탐색한 파일을 기록 (nil 이면 기록하지 않음)
+func (w *Watcher) scan(dir string, videoCh chan<- *job.Job, seen map[string]bool) error {
+	return filepath.Walk(dir, func(videoPath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			return nil
+		}
+
+		if seen != nil {
+			seen[videoPath] = true
+		}
+
+		return w.register(videoPath, info, videoCh)
+	})
 }
 
-func (w *Watcher) checkVideoFile(filename string) bool {
-	allowedExtensions := []string{".mp4", ".mkv", ".avi", ".mov"}
+// register 작업 대상 영상이면 job 을 생성하여 videoCh 로 전달, videoCh 가 가득 차 있으면 errQueueFull
+func (w *Watcher) register(videoPath string, info os.FileInfo, videoCh chan<- *job.Job) error {
+	if w.isIgnored(videoPath) {
+		return nil
+	}
+
+	filename := info.Name()
+	if !IsMediaFile(filename, w.cfg.MediaExtensions, w.cfg.ExcludeExtensions) {
+		return nil
+	}
+
+	alreadyProcess := w.processed.IsProcessed(videoPath, process.WATCHER_FILE_REGISTER)
+	if alreadyProcess && !w.processed.IsRetryable(videoPath) {
+		w.stability.forget(videoPath)
+		return nil
+	}
+
+	if cap(videoCh) > 0 && len(videoCh) >= cap(videoCh) {
+		// event 모드에서는 대기 목록에 남겨 queue 에 자리가 나면 다시 확인
+		w.stability.track(videoPath, info, "queue_full")
+		return errQueueFull
+	}
+
+	if !w.stability.isStable(videoPath, info) {
+		return nil
+	}
+
+	profile := w.profiles.Match(videoPath)
+	jobs := job.NewJob(videoPath, filename)
+	jobs.SetProfile(profile)
+	w.processed.MarkProcessed(jobs, process.WATCHER_FILE_REGISTER)
+
+	select {
+	case videoCh <- jobs:
+	default:
+		// 확인 이후 api 등록 등으로 queue 가 찬 경우 등록을 되돌림
+		w.processed.Forget(videoPath)
+		w.stability.track(videoPath, info, "queue_full")
+		return errQueueFull
+	}
+	slog.Info("watcher new file", "rid", jobs.GetRID(), "watcher_dir", profile.WatchDir, "profile", profile.Name, "filename", filename, "video_path", jobs.GetVideoPath(), "step", process.WATCHER_FILE_REGISTER, "queue_depth", len(videoCh))
+	return nil
+}
+
+func (w *Watcher) isIgnored(path string) bool {
+	return strings.Contains(path, w.cfg.IgnoreDir)
+}
+
+// IsMediaFile 작업 후보 파일인지 확인, 실제 미디어 여부는 extractor 에서 ffprobe 로 확인
+// 숨김 파일(rsync 임시 파일 등)과 시스템 파일은 제외, extensions 가 비어 있거나 "*" 를 포함하면 excludes 에 없는 모든 파일이 후보
+func IsMediaFile(filename string, extensions, excludes []string) bool {
+	if isHiddenOrTemp(filename) {
+		return false
+	}
+
 	ext := strings.ToLower(filepath.Ext(filename))
-	for _, extAllowed := range allowedExtensions {
-		if ext == extAllowed {
+	if ext != "" && containsExt(excludes, ext) {
+		return false
+	}
+	if len(extensions) == 0 || containsExt(extensions, "*") {
+		return true
+	}
+	return ext != "" && containsExt(extensions, ext)
+}
+
+// isHiddenOrTemp 숨김 파일(.DS_Store, rsync 의 .name.XXXXXX 등), 편집기 백업 파일, OS 가 만드는 파일
+func isHiddenOrTemp(filename string) bool {
+	if filename == "" || strings.HasPrefix(filename, ".") || strings.HasSuffix(filename, "~") {
+		return true
+	}
+	for _, name := range systemFiles {
+		if strings.EqualFold(filename, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsExt(extensions []string, ext string) bool {
+	for _, extension := range extensions {
+		if strings.ToLower(strings.TrimSpace(extension)) == ext {
 			return true
 		}
 	}
@@ -0,0 +1,89 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+	"video-ai-stt/config"
+	"video-ai-stt/internal/job"
+	"video-ai-stt/internal/process"
+)
+
+func TestIsMediaFile(t *testing.T) {
+	excludes := []string{".srt", ".json", ".TXT"}
+
+	tests := []struct {
+		name       string
+		filename   string
+		extensions []string
+		want       bool
+	}{
+		{"no filter accepts unknown extension", "recording.dat", nil, true},
+		{"no filter accepts no extension", "recording", nil, true},
+		{"excluded extension", "lecture.srt", nil, false},
+		{"excluded extension is case insensitive", "notes.txt", nil, false},
+		{"empty filename", "", nil, false},
+		{"pre-filter match", "lecture.MP4", []string{".mp4"}, true},
+		{"pre-filter miss", "lecture.mkv", []string{".mp4"}, false},
+		{"pre-filter without extension", "lecture", []string{".mp4"}, false},
+		{"wildcard", "lecture.bin", []string{"*"}, true},
+		{"exclude wins over pre-filter", "lecture.json", []string{".json", ".mp4"}, false},
+		{"dotfile", ".DS_Store", nil, false},
+		{"rsync temp file", ".lecture.mp4.a1B2c3", []string{".mp4"}, false},
+		{"rsync temp file without filter", ".lecture.mp4.a1B2c3", nil, false},
+		{"editor backup", "lecture.mp4~", nil, false},
+		{"system file", "thumbs.db", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsMediaFile(tt.filename, tt.extensions, excludes); got != tt.want {
+				t.Errorf("IsMediaFile(%q, %q) = %v, want %v", tt.filename, tt.extensions, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEventFailureFallsBackToPoll(t *testing.T) {
+	openEventSource = func(roots []string, skip func(string) bool) (eventSource, error) {
+		return nil, fmt.Errorf("failed inotify add watch: %w", syscall.ENOSPC)
+	}
+	t.Cleanup(func() { openEventSource = newEventSource })
+
+	store, err := process.NewStore(config.Store{Type: process.STORE_TYPE_MEMORY})
+	if err != nil {
+		t.Fatal(err)
+	}
+	manager, err := process.NewProcessedManager(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "a.mp4"))
+	w := NewWatcher(config.WatcherFiles{WatcherDir: dir, IgnoreDir: ".working", WatchMode: WATCH_MODE_EVENT, WatchInterval: 1}, manager)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	videoCh := make(chan *job.Job, 1)
+	done := make(chan error, 1)
+	go func() { done <- w.Process(ctx, videoCh) }()
+
+	select {
+	case jobs := <-videoCh:
+		if jobs.GetFilename() != "a.mp4" {
+			t.Errorf("registered %s", jobs.GetFilename())
+		}
+	case err := <-done:
+		t.Fatalf("watcher stopped instead of polling: %v", err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("file not registered by poll fallback")
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Process() = %v after cancel", err)
+	}
+}
@@ -0,0 +1,113 @@
+package whisper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"video-ai-stt/config"
+	"video-ai-stt/internal/stt"
+)
+
+// Whisper 자체 호스팅 whisper.cpp server(/inference) 를 사용하는 Transcriber
+// faster-whisper 계열처럼 openai 호환 api 를 제공하는 서버는 openai provider 의 endpoint 를 변경하여 사용
+type Whisper struct {
+	cfg   config.Whisper
+	retry stt.RetryPolicy
+}
+
+func NewWhisper(cfg config.Whisper, retry stt.RetryPolicy) *Whisper {
+	return &Whisper{
+		cfg:   cfg,
+		retry: retry,
+	}
+}
+
+func (w *Whisper) Name() string {
+	return stt.PROVIDER_WHISPER
+}
+
+func (w *Whisper) Transcribe(ctx context.Context, audioPath string, opts stt.Options) (*stt.Transcript, error) {
+
+	// whisper.cpp server 는 기동 시 로드한 모델을 사용하므로 model 은 기록용
+	model := w.cfg.STTUseModel
+	if opts.Model != "" {
+		model = opts.Model
+	}
+
+	logger := slog.With("rid", opts.RID, "audio_path", audioPath, "provider", w.Name(), "model", model)
+
+	body, err := stt.PostMultipart(ctx, logger, w.retry, stt.Request{
+		Endpoint: w.cfg.STTEndpoint,
+		APIToken: w.cfg.APIToken,
+		FilePath: audioPath,
+		Progress: opts.Progress,
+		Fields: append([]stt.Field{
+			{Name: "temperature", Value: "0"},
+			{Name: "response_format", Value: "verbose_json"},
+		}, opts.Fields()...),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := inferenceResp{}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("%w: failed unmarshalling response: %w, body : %s", stt.ErrInvalidResponse, err, string(body))
+	}
+
+	logger.Info("whisper inference result", "duration", resp.Duration, "language", resp.Language)
+	return resp.toTranscript(model), nil
+}
+
+type inferenceResp struct {
+	Task     string    `json:"task"`
+	Language string    `json:"language"`
+	Duration float64   `json:"duration"`
+	Text     string    `json:"text"`
+	Segments []segment `json:"segments"`
+}
+
+// segment whisper.cpp 는 단어 타임스탬프를 세그먼트 하위에 포함
+type segment struct {
+	ID           int64   `json:"id"`
+	Start        float64 `json:"start"`
+	End          float64 `json:"end"`
+	Text         string  `json:"text"`
+	AvgLogProb   float64 `json:"avg_logprob"`
+	NoSpeechProb float64 `json:"no_speech_prob"`
+	Words        []word  `json:"words"`
+}
+
+type word struct {
+	Word        string  `json:"word"`
+	Start       float64 `json:"start"`
+	End         float64 `json:"end"`
+	Probability float64 `json:"probability"`
+}
+
+func (r *inferenceResp) toTranscript(model string) *stt.Transcript {
+	transcript := &stt.Transcript{
+		Provider: stt.PROVIDER_WHISPER,
+		Model:    model,
+		Language: r.Language,
+		Duration: r.Duration,
+		Text:     r.Text,
+		Segments: make([]stt.Segment, 0, len(r.Segments)),
+	}
+
+	for _, s := range r.Segments {
+		transcript.Segments = append(transcript.Segments, stt.Segment{
+			ID:           s.ID,
+			Start:        s.Start,
+			End:          s.End,
+			Text:         s.Text,
+			AvgLogProb:   s.AvgLogProb,
+			NoSpeechProb: s.NoSpeechProb,
+		})
+		for _, w := range s.Words {
+			transcript.Words = append(transcript.Words, stt.Word{Word: w.Word, Start: w.Start, End: w.End})
+		}
+	}
+	return transcript
+}